@@ -0,0 +1,127 @@
+package delaunay
+
+import (
+	"math"
+	"sort"
+)
+
+func (t *Triangulation) circumradii() []float64 {
+	points := t.Points
+	ts := t.Triangles
+	radii := make([]float64, len(ts)/3)
+	for i := range radii {
+		p0 := points[ts[i*3+0]]
+		p1 := points[ts[i*3+1]]
+		p2 := points[ts[i*3+2]]
+		radii[i] = math.Sqrt(circumradius(p0, p1, p2))
+	}
+	return radii
+}
+
+// AlphaShape returns the boundary of the alpha shape of the triangulation,
+// which is the union of the triangles whose circumradius does not exceed
+// alpha. The boundary may consist of several rings; like ConvexHull, outer
+// rings are counter-clockwise while the rings around holes are clockwise.
+func (t *Triangulation) AlphaShape(alpha float64) [][]Point {
+	radii := t.circumradii()
+	keep := make([]bool, len(radii))
+	for i, r := range radii {
+		keep[i] = r <= alpha
+	}
+	return t.ringPoints(t.rings(keep))
+}
+
+// OptimalAlpha returns the smallest alpha for which the alpha shape is a
+// single connected region that covers every point of the triangulation.
+func (t *Triangulation) OptimalAlpha() float64 {
+	radii := t.circumradii()
+	ids := make([]int, len(radii))
+	for i := range ids {
+		ids[i] = i
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return radii[ids[i]] < radii[ids[j]]
+	})
+
+	// count the points that should be covered
+	ts := t.Triangles
+	covered := make([]bool, len(t.Points))
+	remaining := 0
+	for _, i := range ts {
+		if !covered[i] {
+			covered[i] = true
+			remaining++
+		}
+	}
+	for i := range covered {
+		covered[i] = false
+	}
+
+	// add triangles in order of their circumradius until they form a single
+	// component that covers every point
+	uf := newUnionFind(len(radii))
+	added := make([]bool, len(radii))
+	components := 0
+	for _, i := range ids {
+		added[i] = true
+		components++
+		for j := i * 3; j < i*3+3; j++ {
+			if !covered[ts[j]] {
+				covered[ts[j]] = true
+				remaining--
+			}
+			if h := t.Halfedges[j]; h >= 0 && added[h/3] && uf.union(i, h/3) {
+				components--
+			}
+		}
+		if components == 1 && remaining == 0 {
+			return radii[i]
+		}
+	}
+	return 0
+}
+
+// rings traces the boundaries of the union of the kept triangles and returns
+// them as rings of point indices, outer rings counter-clockwise
+func (t *Triangulation) rings(keep []bool) [][]int {
+	hs := t.Halfedges
+	ts := t.Triangles
+	boundary := func(e int) bool {
+		return keep[e/3] && (hs[e] < 0 || !keep[hs[e]/3])
+	}
+
+	var result [][]int
+	visited := make([]bool, len(hs))
+	for start := range hs {
+		if visited[start] || !boundary(start) {
+			continue
+		}
+		var ring []int
+		e := start
+		for !visited[e] {
+			visited[e] = true
+			ring = append(ring, ts[e])
+
+			// the triangles wind clockwise, so walk the boundary backwards:
+			// rotate around the start point to the previous boundary halfedge
+			e = prevHalfedge(e)
+			for !boundary(e) {
+				e = prevHalfedge(hs[e])
+			}
+		}
+
+		result = append(result, ring)
+	}
+	return result
+}
+
+func (t *Triangulation) ringPoints(rings [][]int) [][]Point {
+	result := make([][]Point, len(rings))
+	for i, ring := range rings {
+		result[i] = make([]Point, len(ring))
+		for j, k := range ring {
+			result[i][j] = t.Points[k]
+		}
+	}
+	return result
+}
@@ -0,0 +1,186 @@
+package delaunay
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"math"
+)
+
+// The binary format starts with a magic number and a version byte, followed
+// by the points and the convex hull as pairs of little endian float64s and
+// by the triangles as zigzag varint deltas between consecutive indices.
+// Halfedge e is stored as the zigzag varint of Halfedges[e] - e, or 0 if
+// there is no twin, since a halfedge is never its own twin. Each section is
+// preceded by its length as a uvarint, and the data ends with the IEEE
+// CRC-32 of everything before it.
+const (
+	binaryMagic   = "DLNY"
+	binaryVersion = 1
+)
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (t *Triangulation) MarshalBinary() ([]byte, error) {
+	if len(t.Halfedges) != len(t.Triangles) {
+		return nil, fmt.Errorf("expected %d halfedges, got %d", len(t.Triangles), len(t.Halfedges))
+	}
+	n := 5 + 16*(len(t.Points)+len(t.ConvexHull)) + 2*len(t.Triangles) + 4*binary.MaxVarintLen64 + 4
+	buf := make([]byte, 0, n)
+	buf = append(buf, binaryMagic...)
+	buf = append(buf, binaryVersion)
+
+	var scratch [binary.MaxVarintLen64]byte
+	uvarint := func(x uint64) {
+		buf = append(buf, scratch[:binary.PutUvarint(scratch[:], x)]...)
+	}
+	varint := func(x int64) {
+		buf = append(buf, scratch[:binary.PutVarint(scratch[:], x)]...)
+	}
+	points := func(points []Point) {
+		uvarint(uint64(len(points)))
+		for _, p := range points {
+			binary.LittleEndian.PutUint64(scratch[:], math.Float64bits(p.X))
+			buf = append(buf, scratch[:8]...)
+			binary.LittleEndian.PutUint64(scratch[:], math.Float64bits(p.Y))
+			buf = append(buf, scratch[:8]...)
+		}
+	}
+
+	points(t.Points)
+	points(t.ConvexHull)
+	uvarint(uint64(len(t.Triangles)))
+	previous := 0
+	for _, i := range t.Triangles {
+		varint(int64(i - previous))
+		previous = i
+	}
+	for e, h := range t.Halfedges {
+		if h < 0 {
+			varint(0)
+		} else {
+			varint(int64(h - e))
+		}
+	}
+
+	binary.LittleEndian.PutUint32(scratch[:], crc32.ChecksumIEEE(buf))
+	return append(buf, scratch[:4]...), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. It verifies the
+// checksum and the indices, and returns the error from Validate, with
+// tolerances relative to the size and position of the points, if the
+// decoded triangulation is not valid.
+func (t *Triangulation) UnmarshalBinary(data []byte) error {
+	if len(data) < len(binaryMagic)+5 || string(data[:len(binaryMagic)]) != binaryMagic {
+		return fmt.Errorf("not a binary triangulation")
+	}
+	if v := data[len(binaryMagic)]; v != binaryVersion {
+		return fmt.Errorf("unsupported binary triangulation version: %d", v)
+	}
+	body := data[:len(data)-4]
+	if crc32.ChecksumIEEE(body) != binary.LittleEndian.Uint32(data[len(body):]) {
+		return fmt.Errorf("binary triangulation checksum mismatch")
+	}
+
+	buf := body[len(binaryMagic)+1:]
+	errTruncated := fmt.Errorf("truncated binary triangulation")
+	length := func(size int) (int, error) {
+		x, n := binary.Uvarint(buf)
+		if n <= 0 || x > uint64(len(buf)-n)/uint64(size) {
+			return 0, errTruncated
+		}
+		buf = buf[n:]
+		return int(x), nil
+	}
+	varint := func() (int, error) {
+		x, n := binary.Varint(buf)
+		if n <= 0 {
+			return 0, errTruncated
+		}
+		buf = buf[n:]
+		return int(x), nil
+	}
+	points := func() ([]Point, error) {
+		n, err := length(16)
+		if err != nil {
+			return nil, err
+		}
+		result := make([]Point, n)
+		for i := range result {
+			x := math.Float64frombits(binary.LittleEndian.Uint64(buf))
+			y := math.Float64frombits(binary.LittleEndian.Uint64(buf[8:]))
+			result[i] = Point{x, y}
+			buf = buf[16:]
+		}
+		return result, nil
+	}
+
+	ps, err := points()
+	if err != nil {
+		return err
+	}
+	hull, err := points()
+	if err != nil {
+		return err
+	}
+	n, err := length(2)
+	if err != nil {
+		return err
+	}
+	if n%3 != 0 {
+		return fmt.Errorf("invalid number of triangle indices: %d", n)
+	}
+	triangles := make([]int, n)
+	previous := 0
+	for k := range triangles {
+		d, err := varint()
+		if err != nil {
+			return err
+		}
+		i := previous + d
+		if i < 0 || i >= len(ps) {
+			return fmt.Errorf("point index %d out of range", i)
+		}
+		triangles[k] = i
+		previous = i
+	}
+	halfedges := make([]int, n)
+	for e := range halfedges {
+		d, err := varint()
+		if err != nil {
+			return err
+		}
+		h := -1
+		if d != 0 {
+			h = e + d
+		}
+		if h < -1 || h >= n {
+			return fmt.Errorf("halfedge index %d out of range", h)
+		}
+		halfedges[e] = h
+	}
+	if len(buf) != 0 {
+		return fmt.Errorf("unexpected data after binary triangulation")
+	}
+
+	// the rounding errors of the hull areas and perimeters grow with the
+	// size of the points and their distance from the origin, which is large
+	// for projected coordinates, so scale the tolerances of Validate by them
+	var extent, offset float64
+	if len(ps) > 0 {
+		min, max := ps[0], ps[0]
+		for _, p := range ps {
+			min = Point{math.Min(min.X, p.X), math.Min(min.Y, p.Y)}
+			max = Point{math.Max(max.X, p.X), math.Max(max.Y, p.Y)}
+		}
+		extent = math.Max(max.X-min.X, max.Y-min.Y)
+		offset = math.Max(math.Max(math.Abs(min.X), math.Abs(max.X)), math.Max(math.Abs(min.Y), math.Abs(max.Y)))
+	}
+	scale := math.Max(1, math.Max(extent, offset))
+	result := Triangulation{ps, hull, triangles, halfedges}
+	if err := result.validate(1e-9*scale*math.Max(1, extent), 1e-9*scale); err != nil {
+		return err
+	}
+	*t = result
+	return nil
+}
@@ -0,0 +1,131 @@
+package delaunay
+
+import "math"
+
+// cells builds the dual cell of every point of the triangulation, given one
+// center per triangle (circumcenters for a Voronoi diagram, power centers for
+// a power diagram). Cells are counter-clockwise and clipped to the rectangle
+// [min, max]; the unbounded cells of points on the convex hull are closed by
+// that rectangle. Points that are not part of the triangulation, or whose
+// cells lie entirely outside of the rectangle, have nil cells.
+func cells(t *Triangulation, centers []Point, min, max Point) [][]Point {
+	ts := t.Triangles
+	hs := t.Halfedges
+
+	// find one outgoing halfedge for each point, preferring the halfedge on
+	// the convex hull so that walks around hull points cover every triangle
+	edges := make([]int, len(t.Points))
+	for i := range edges {
+		edges[i] = -1
+	}
+	for e, i := range ts {
+		if edges[i] < 0 || hs[e] < 0 {
+			edges[i] = e
+		}
+	}
+
+	// points far enough away to stand in for the ends of unbounded cells
+	origin := Point{(min.X + max.X) / 2, (min.Y + max.Y) / 2}
+	radius := min.distance(max)
+	for _, c := range centers {
+		radius = math.Max(radius, c.distance(origin))
+	}
+	radius *= 4
+
+	result := make([][]Point, len(t.Points))
+	for i, start := range edges {
+		if start < 0 {
+			continue
+		}
+
+		// walk around the point; the triangles wind clockwise around it
+		var cell []Point
+		e := start
+		for {
+			cell = append(cell, centers[e/3])
+			e = hs[prevHalfedge(e)]
+			if e == start || e < 0 {
+				break
+			}
+		}
+
+		if hs[start] < 0 {
+			// the cell is unbounded: extend it along the outward normals
+			// of the two hull edges incident to the point
+			p := t.Points[i]
+			q := t.Points[ts[nextHalfedge(start)]]
+			r := t.Points[ts[prevHalfedge(lastOutgoing(hs, start))]]
+			n0 := Point{p.Y - q.Y, q.X - p.X}
+			n1 := Point{r.Y - p.Y, p.X - r.X}
+			n0 = n0.scale(radius / n0.length())
+			n1 = n1.scale(radius / n1.length())
+			mid := n0.add(n1)
+			mid = mid.scale(radius / mid.length())
+			first := cell[0]
+			last := cell[len(cell)-1]
+			cell = append(cell, last.add(n1), origin.add(mid).add(mid), first.add(n0))
+		}
+
+		result[i] = clipPolygon(reversed(cell), min, max)
+	}
+	return result
+}
+
+// lastOutgoing returns the last outgoing halfedge reached when walking around
+// the start point of e, which lies on the convex hull
+func lastOutgoing(halfedges []int, e int) int {
+	for {
+		h := halfedges[prevHalfedge(e)]
+		if h < 0 {
+			return e
+		}
+		e = h
+	}
+}
+
+// clipPolygon clips a convex polygon to the rectangle [min, max] using the
+// Sutherland-Hodgman algorithm
+func clipPolygon(polygon []Point, min, max Point) []Point {
+	inside := []func(p Point) bool{
+		func(p Point) bool { return p.X >= min.X },
+		func(p Point) bool { return p.X <= max.X },
+		func(p Point) bool { return p.Y >= min.Y },
+		func(p Point) bool { return p.Y <= max.Y },
+	}
+	intersect := []func(a, b Point) Point{
+		func(a, b Point) Point { return lerpX(a, b, min.X) },
+		func(a, b Point) Point { return lerpX(a, b, max.X) },
+		func(a, b Point) Point { return lerpY(a, b, min.Y) },
+		func(a, b Point) Point { return lerpY(a, b, max.Y) },
+	}
+	for k := range inside {
+		if len(polygon) == 0 {
+			break
+		}
+		var result []Point
+		a := polygon[len(polygon)-1]
+		for _, b := range polygon {
+			if inside[k](b) {
+				if !inside[k](a) {
+					result = append(result, intersect[k](a, b))
+				}
+				result = append(result, b)
+			} else if inside[k](a) {
+				result = append(result, intersect[k](a, b))
+			}
+			a = b
+		}
+		polygon = result
+	}
+	return polygon
+}
+
+func lerpX(a, b Point, x float64) Point {
+	t := (x - a.X) / (b.X - a.X)
+	return Point{x, a.Y + t*(b.Y-a.Y)}
+}
+
+func lerpY(a, b Point, y float64) Point {
+	t := (y - a.Y) / (b.Y - a.Y)
+	return Point{a.X + t*(b.X-a.X), y}
+}
@@ -0,0 +1,89 @@
+package delaunay
+
+// Clustering is the result of single-linkage clustering of the points of a
+// triangulation.
+type Clustering struct {
+	// Labels holds the cluster of each point, numbered from zero in order of
+	// their lowest point index, or -1 for points that are not part of the
+	// triangulation (duplicates).
+	Labels []int
+
+	// Outlines holds the boundary rings of each cluster: the union of the
+	// triangles whose edges are all within the distance threshold. Like
+	// AlphaShape, outer rings are counter-clockwise and holes are clockwise.
+	// Clusters that contain no such triangles have no outlines.
+	Outlines [][][]Point
+}
+
+// Cluster groups the triangulated points into clusters that are connected by
+// Delaunay edges no longer than threshold, which is single-linkage
+// clustering with that distance threshold.
+func (t *Triangulation) Cluster(threshold float64) *Clustering {
+	points := t.Points
+	ts := t.Triangles
+	uf := newUnionFind(len(points))
+	for _, e := range t.edges() {
+		if points[e[0]].distance(points[e[1]]) <= threshold {
+			uf.union(e[0], e[1])
+		}
+	}
+
+	// number the clusters
+	labels := make([]int, len(points))
+	for i := range labels {
+		labels[i] = -1
+	}
+	for _, i := range ts {
+		labels[i] = 0
+	}
+	ids := make(map[int]int)
+	for i, label := range labels {
+		if label < 0 {
+			continue
+		}
+		root := uf.find(i)
+		id, ok := ids[root]
+		if !ok {
+			id = len(ids)
+			ids[root] = id
+		}
+		labels[i] = id
+	}
+
+	// outline the triangles whose edges are all within the threshold
+	keep := make([]bool, len(ts)/3)
+	for i := range keep {
+		keep[i] = true
+		for e := i * 3; e < i*3+3; e++ {
+			if points[ts[e]].distance(points[ts[nextHalfedge(e)]]) > threshold {
+				keep[i] = false
+			}
+		}
+	}
+	outlines := make([][][]Point, len(ids))
+	rings := t.rings(keep)
+	for i, ring := range t.ringPoints(rings) {
+		label := labels[rings[i][0]]
+		outlines[label] = append(outlines[label], ring)
+	}
+
+	return &Clustering{labels, outlines}
+}
+
+// ClusterCount groups the triangulated points into k clusters by removing
+// the k-1 longest edges of the Euclidean minimum spanning tree. The
+// clustering is then the same as Cluster with a threshold of the longest
+// remaining edge, so ties in edge lengths can produce fewer clusters.
+func (t *Triangulation) ClusterCount(k int) *Clustering {
+	points := t.Points
+	edges, _ := t.EMST()
+	threshold := -1.0
+	if i := len(edges) - k; i >= 0 && len(edges) > 0 {
+		if i >= len(edges) {
+			i = len(edges) - 1
+		}
+		e := edges[i]
+		threshold = points[e[0]].distance(points[e[1]])
+	}
+	return t.Cluster(threshold)
+}
@@ -0,0 +1,301 @@
+// Command delaunay triangulates points read from a file or stdin and writes
+// the triangulation, or a structure derived from it, in one of several
+// formats.
+//
+//	$ delaunay -o svg points.csv > out.svg
+//	$ cat points.geojson | delaunay -mode voronoi -o geojson
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fogleman/delaunay"
+)
+
+var (
+	inputFormat  = flag.String("i", "auto", "input format: auto, csv, text or geojson")
+	outputFormat = flag.String("o", "text", "output format: text, geojson, wkt, svg, obj, ply, vtk, vtu or binary")
+	outputPath   = flag.String("out", "", "output file (default stdout)")
+	mode         = flag.String("mode", "triangles", "output: triangles, hull, voronoi or alpha")
+	alpha        = flag.Float64("alpha", 0, "alpha for -mode alpha (default: the optimal alpha)")
+	validate     = flag.Bool("validate", false, "validate the triangulation and fail if it is invalid")
+	timing       = flag.Bool("time", false, "print timings to stderr")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] [file]\n\n", os.Args[0])
+		fmt.Fprintln(flag.CommandLine.Output(), "Reads points from file, or from stdin if file is omitted or \"-\".")
+		fmt.Fprintln(flag.CommandLine.Output())
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(flag.Arg(0)); err != nil {
+		fmt.Fprintln(os.Stderr, "delaunay:", err)
+		os.Exit(1)
+	}
+}
+
+func run(path string) error {
+	start := time.Now()
+	points, properties, err := readPoints(path)
+	if err != nil {
+		return err
+	}
+	report("read %d points", start, len(points))
+
+	start = time.Now()
+	t, err := delaunay.Triangulate(points)
+	if err != nil {
+		return err
+	}
+	report("triangulated %d triangles", start, len(t.Triangles)/3)
+
+	if *validate {
+		start = time.Now()
+		if err := t.Validate(); err != nil {
+			return err
+		}
+		report("validated", start)
+	}
+
+	start = time.Now()
+	var buf bytes.Buffer
+	if err := write(&buf, t, properties, *mode, *outputFormat, *alpha); err != nil {
+		return err
+	}
+	n := buf.Len()
+	if *outputPath == "" {
+		if _, err := buf.WriteTo(os.Stdout); err != nil {
+			return err
+		}
+	} else if err := os.WriteFile(*outputPath, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	report("wrote %d bytes", start, n)
+	return nil
+}
+
+func report(format string, start time.Time, a ...interface{}) {
+	if *timing {
+		fmt.Fprintf(os.Stderr, "%s in %v\n", fmt.Sprintf(format, a...), time.Since(start))
+	}
+}
+
+func readPoints(path string) ([]delaunay.Point, []map[string]interface{}, error) {
+	var data []byte
+	var err error
+	if path == "" || path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	format := *inputFormat
+	if format == "auto" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".geojson", ".json":
+			format = "geojson"
+		case ".csv":
+			format = "csv"
+		default:
+			if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+				format = "geojson"
+			} else {
+				format = "text"
+			}
+		}
+	}
+
+	switch format {
+	case "geojson":
+		return delaunay.ReadGeoJSON(bytes.NewReader(data))
+	case "csv", "text":
+		points, err := readText(data)
+		return points, nil, err
+	}
+	return nil, nil, fmt.Errorf("unknown input format: %q", format)
+}
+
+// readText reads one point per line from the first two fields, separated by
+// commas, semicolons or whitespace. Empty lines and comments starting with
+// '#' are skipped anywhere, as is a header: a first line, other than those,
+// whose fields are not numbers.
+func readText(data []byte) ([]delaunay.Point, error) {
+	var points []delaunay.Point
+	header := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.FieldsFunc(text, func(r rune) bool {
+			return r == ',' || r == ';' || r == ' ' || r == '\t'
+		})
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("line %d: expected at least 2 fields", line)
+		}
+		x, errX := strconv.ParseFloat(fields[0], 64)
+		y, errY := strconv.ParseFloat(fields[1], 64)
+		if errX != nil || errY != nil {
+			if !header && len(points) == 0 {
+				header = true
+				continue
+			}
+			return nil, fmt.Errorf("line %d: invalid point: %q", line, text)
+		}
+		points = append(points, delaunay.Point{X: x, Y: y})
+	}
+	return points, scanner.Err()
+}
+
+// write writes the output of the mode in the format; alpha is used by the
+// alpha mode, or the optimal alpha if it is not positive
+func write(w io.Writer, t *delaunay.Triangulation, properties []map[string]interface{}, mode, format string, alpha float64) error {
+	unsupported := fmt.Errorf("output format %q is not supported with -mode %s", format, mode)
+	switch mode {
+	case "triangles":
+		switch format {
+		case "text":
+			ts := t.Triangles
+			bw := bufio.NewWriter(w)
+			for i := 0; i < len(ts); i += 3 {
+				fmt.Fprintf(bw, "%d %d %d\n", ts[i], ts[i+1], ts[i+2])
+			}
+			return bw.Flush()
+		case "geojson":
+			return t.WriteGeoJSON(w)
+		case "wkt":
+			_, err := fmt.Fprintln(w, t.WKT())
+			return err
+		case "svg":
+			options := delaunay.DefaultSVGOptions()
+			return t.WriteSVG(w, options)
+		case "obj":
+			return t.WriteOBJ(w, nil, nil)
+		case "ply":
+			return t.WritePLY(w, nil, nil, false)
+		case "vtk":
+			return t.WriteVTK(w, nil, nil)
+		case "vtu":
+			return t.WriteVTU(w, nil, nil)
+		case "binary":
+			data, err := t.MarshalBinary()
+			if err != nil {
+				return err
+			}
+			_, err = w.Write(data)
+			return err
+		}
+		return unsupported
+
+	case "hull":
+		switch format {
+		case "text":
+			return writeRings(w, [][]delaunay.Point{t.ConvexHull})
+		case "geojson":
+			return t.WriteHullGeoJSON(w)
+		case "wkt":
+			_, err := fmt.Fprintln(w, t.HullWKT())
+			return err
+		case "svg":
+			options := delaunay.DefaultSVGOptions()
+			options.Edges = nil
+			return t.WriteSVG(w, options)
+		}
+		return unsupported
+
+	case "voronoi":
+		min, max := bounds(t.Points)
+		switch format {
+		case "text":
+			return writeRings(w, t.VoronoiCells(min, max))
+		case "geojson":
+			return t.WriteVoronoiGeoJSON(w, min, max, properties)
+		case "svg":
+			options := delaunay.DefaultSVGOptions()
+			options.Edges = nil
+			options.Hull = nil
+			options.Voronoi = &delaunay.SVGStyle{Stroke: "black", StrokeWidth: 1}
+			return t.WriteSVG(w, options)
+		}
+		return unsupported
+
+	case "alpha":
+		if alpha <= 0 {
+			alpha = t.OptimalAlpha()
+		}
+		rings := t.AlphaShape(alpha)
+		switch format {
+		case "text":
+			return writeRings(w, rings)
+		case "geojson":
+			return writeRingsGeoJSON(w, rings)
+		}
+		return unsupported
+	}
+	return fmt.Errorf("unknown mode: %q", mode)
+}
+
+// bounds returns the bounding box of the points, padded by 10%
+func bounds(points []delaunay.Point) (delaunay.Point, delaunay.Point) {
+	min := delaunay.Point{X: math.Inf(1), Y: math.Inf(1)}
+	max := delaunay.Point{X: math.Inf(-1), Y: math.Inf(-1)}
+	for _, p := range points {
+		min = delaunay.Point{X: math.Min(min.X, p.X), Y: math.Min(min.Y, p.Y)}
+		max = delaunay.Point{X: math.Max(max.X, p.X), Y: math.Max(max.Y, p.Y)}
+	}
+	dx := (max.X - min.X) / 10
+	dy := (max.Y - min.Y) / 10
+	return delaunay.Point{X: min.X - dx, Y: min.Y - dy}, delaunay.Point{X: max.X + dx, Y: max.Y + dy}
+}
+
+// writeRings writes one point per line, with an empty line after each ring
+func writeRings(w io.Writer, rings [][]delaunay.Point) error {
+	bw := bufio.NewWriter(w)
+	for _, ring := range rings {
+		for _, p := range ring {
+			fmt.Fprintf(bw, "%g %g\n", p.X, p.Y)
+		}
+		fmt.Fprintln(bw)
+	}
+	return bw.Flush()
+}
+
+// writeRingsGeoJSON writes the rings as closed LineStrings
+func writeRingsGeoJSON(w io.Writer, rings [][]delaunay.Point) error {
+	type m = map[string]interface{}
+	features := []m{}
+	for _, ring := range rings {
+		var coordinates [][]float64
+		for i := 0; i <= len(ring); i++ {
+			p := ring[i%len(ring)]
+			coordinates = append(coordinates, []float64{p.X, p.Y})
+		}
+		features = append(features, m{
+			"type":       "Feature",
+			"geometry":   m{"type": "LineString", "coordinates": coordinates},
+			"properties": m{},
+		})
+	}
+	return json.NewEncoder(w).Encode(m{"type": "FeatureCollection", "features": features})
+}
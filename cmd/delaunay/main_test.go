@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fogleman/delaunay"
+)
+
+func TestReadText(t *testing.T) {
+	square := []delaunay.Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: 1}}
+	inputs := []string{
+		"0 0\n1 0\n1 1\n0 1\n",
+		"x,y\n0,0\n1,0\n1,1\n0,1\n",
+		"# exported points\n\nx;y;name\n0;0;a\n1;0;b\n# more\n1;1;c\n0\t1\td\n",
+		"  0   0  \r\n1 0 5\r\n1 1 5\r\n0 1 5\r\n",
+	}
+	for _, input := range inputs {
+		points, err := readText([]byte(input))
+		if err != nil {
+			t.Fatalf("%q: %v", input, err)
+		}
+		if !reflect.DeepEqual(points, square) {
+			t.Fatalf("%q: unexpected points: %v", input, points)
+		}
+	}
+	for _, input := range []string{"x y\n0 0\nnan? 1\n", "x y\nx y\n0 0\n", "0\n"} {
+		if _, err := readText([]byte(input)); err == nil {
+			t.Fatalf("%q: expected an error", input)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	points := []delaunay.Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: 1}, {X: 0.5, Y: 0.4}}
+	tri, err := delaunay.Triangulate(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	formats := map[string][]string{
+		"triangles": {"text", "geojson", "wkt", "svg", "obj", "ply", "vtk", "vtu", "binary"},
+		"hull":      {"text", "geojson", "wkt", "svg"},
+		"voronoi":   {"text", "geojson", "svg"},
+		"alpha":     {"text", "geojson"},
+	}
+	for mode, list := range formats {
+		for _, format := range list {
+			var buf bytes.Buffer
+			if err := write(&buf, tri, nil, mode, format, 0); err != nil {
+				t.Fatalf("%s %s: %v", mode, format, err)
+			}
+			if buf.Len() == 0 {
+				t.Fatalf("%s %s: no output", mode, format)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := write(&buf, tri, nil, "triangles", "text", 0); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(buf.String()), "\n"); len(lines) != 4 {
+		t.Fatalf("expected 4 triangles, got %d", len(lines))
+	}
+	buf.Reset()
+	if err := write(&buf, tri, nil, "hull", "wkt", 0); err != nil || !strings.HasPrefix(buf.String(), "POLYGON") {
+		t.Fatalf("unexpected hull: %q, %v", buf.String(), err)
+	}
+
+	for _, c := range [][2]string{{"alpha", "svg"}, {"voronoi", "wkt"}, {"constrained", "text"}, {"triangles", "png"}} {
+		if err := write(&buf, tri, nil, c[0], c[1], 0); err == nil {
+			t.Fatalf("%s %s: expected an error", c[0], c[1])
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package delaunay
+
+// crossing returns the point where the linearly interpolated value along the
+// edge between points a and b equals level. It is computed in the same way
+// from both sides of the edge so that neighboring triangles agree exactly.
+func (t *Triangulation) crossing(values []float64, a, b int, level float64) Point {
+	if a > b {
+		a, b = b, a
+	}
+	p := t.Points[a]
+	q := t.Points[b]
+	s := (level - values[a]) / (values[b] - values[a])
+	return Point{p.X + (q.X-p.X)*s, p.Y + (q.Y-p.Y)*s}
+}
+
+// Contours returns the contour lines of the scalar field given by one value
+// per point, linearly interpolated across each triangle, at each of the
+// provided levels. result[i] holds the lines for levels[i]. Lines are
+// stitched across triangles and oriented so that higher values are on their
+// left; closed lines end with their first point, while open lines end on the
+// convex hull.
+func (t *Triangulation) Contours(values []float64, levels []float64) [][][]Point {
+	result := make([][][]Point, len(levels))
+	for i, level := range levels {
+		result[i] = t.contour(values, level)
+	}
+	return result
+}
+
+func (t *Triangulation) contour(values []float64, level float64) [][]Point {
+	ts := t.Triangles
+	hs := t.Halfedges
+
+	// find the halfedges where each segment enters and leaves its triangle
+	n := len(ts) / 3
+	in := make([]int, n)
+	out := make([]int, n)
+	for i := 0; i < n; i++ {
+		in[i] = -1
+		var above [3]bool
+		count := 0
+		for j := 0; j < 3; j++ {
+			above[j] = values[ts[i*3+j]] >= level
+			if above[j] {
+				count++
+			}
+		}
+		if count == 0 || count == 3 {
+			continue
+		}
+
+		// the segment crosses the two edges of the vertex that is on its
+		// own side; the triangles wind clockwise, so that vertex is on the
+		// left when going from its incoming edge to its outgoing edge
+		for k := 0; k < 3; k++ {
+			if above[k] == above[(k+1)%3] || above[k] == above[(k+2)%3] {
+				continue
+			}
+			in[i] = i*3 + (k+2)%3
+			out[i] = i*3 + k
+			if !above[k] {
+				in[i], out[i] = out[i], in[i]
+			}
+		}
+	}
+
+	point := func(e int) Point {
+		return t.crossing(values, ts[e], ts[nextHalfedge(e)], level)
+	}
+
+	var lines [][]Point
+	visited := make([]bool, n)
+	follow := func(i int) {
+		line := []Point{point(in[i])}
+		for i >= 0 && !visited[i] {
+			visited[i] = true
+			line = append(line, point(out[i]))
+			if h := hs[out[i]]; h >= 0 {
+				i = h / 3
+			} else {
+				i = -1
+			}
+		}
+		if i >= 0 {
+			// closed line; make the last point identical to the first
+			line[len(line)-1] = line[0]
+		}
+		lines = append(lines, line)
+	}
+
+	// open lines start on the convex hull
+	for i := 0; i < n; i++ {
+		if in[i] >= 0 && hs[in[i]] < 0 {
+			follow(i)
+		}
+	}
+
+	// the remaining segments form closed lines
+	for i := 0; i < n; i++ {
+		if in[i] >= 0 && !visited[i] {
+			follow(i)
+		}
+	}
+
+	return lines
+}
+
+// Isobands returns the filled regions between consecutive levels of the
+// scalar field given by one value per point, linearly interpolated across
+// each triangle. result[i] holds the rings of the region where
+// levels[i] <= value < levels[i+1]; like AlphaShape, outer rings are
+// counter-clockwise and holes are clockwise. The levels must be increasing.
+func (t *Triangulation) Isobands(values []float64, levels []float64) [][][]Point {
+	if len(levels) < 2 {
+		return nil
+	}
+	result := make([][][]Point, len(levels)-1)
+	for i := range result {
+		result[i] = t.isoband(values, levels[i], levels[i+1])
+	}
+	return result
+}
+
+func (t *Triangulation) isoband(values []float64, lo, hi float64) [][]Point {
+	ts := t.Triangles
+	inside := func(i int) bool {
+		return values[i] >= lo && values[i] < hi
+	}
+
+	// the corners of the pieces are identified by the mesh rather than by
+	// their position, so that neighboring triangles agree on them; a is a
+	// point index if b < 0, and otherwise the corner is where the edge
+	// between points a < b crosses the level. Crossings at a point whose
+	// value equals the level are that point.
+	type corner struct {
+		a, b  int
+		level float64
+	}
+	position := func(c corner) Point {
+		if c.b < 0 {
+			return t.Points[c.a]
+		}
+		return t.crossing(values, c.a, c.b, c.level)
+	}
+
+	// clip each triangle to the band and collect the directed edges of the
+	// pieces, cancelling the edges shared by neighboring pieces
+	type edge [2]corner
+	edges := make(map[edge]bool)
+	var order []edge
+	var piece []corner
+	for i := 0; i < len(ts); i += 3 {
+		piece = piece[:0]
+		add := func(c corner) {
+			if len(piece) == 0 || piece[len(piece)-1] != c {
+				piece = append(piece, c)
+			}
+		}
+		for e := i; e < i+3; e++ {
+			a := ts[e]
+			b := ts[nextHalfedge(e)]
+			if inside(a) {
+				add(corner{a, -1, 0})
+			}
+			levels := [2]float64{lo, hi}
+			if values[a] > values[b] {
+				levels[0], levels[1] = hi, lo
+			}
+			for _, level := range levels {
+				if (values[a] < level) != (values[b] < level) {
+					switch {
+					case values[a] == level:
+						add(corner{a, -1, 0})
+					case values[b] == level:
+						add(corner{b, -1, 0})
+					case a < b:
+						add(corner{a, b, level})
+					default:
+						add(corner{b, a, level})
+					}
+				}
+			}
+		}
+		if len(piece) > 1 && piece[0] == piece[len(piece)-1] {
+			piece = piece[:len(piece)-1]
+		}
+		if len(piece) < 3 {
+			continue
+		}
+		for j, p := range piece {
+			q := piece[(j+1)%len(piece)]
+			if edges[edge{q, p}] {
+				delete(edges, edge{q, p})
+			} else {
+				edges[edge{p, q}] = true
+				order = append(order, edge{p, q})
+			}
+		}
+	}
+
+	// every corner has as many incoming as outgoing edges, so a walk along
+	// unused edges always returns to where it started; corners visited
+	// twice (where the band pinches) split off the loop in between
+	next := make(map[corner][]corner)
+	for _, e := range order {
+		if edges[e] {
+			next[e[0]] = append(next[e[0]], e[1])
+		}
+	}
+	var rings [][]Point
+	emit := func(path []corner) {
+		var ring []Point
+		for _, c := range path {
+			p := position(c)
+			if len(ring) == 0 || ring[len(ring)-1] != p {
+				ring = append(ring, p)
+			}
+		}
+		if len(ring) > 1 && ring[0] == ring[len(ring)-1] {
+			ring = ring[:len(ring)-1]
+		}
+		if len(ring) < 3 {
+			return
+		}
+		rings = append(rings, reversed(ring))
+	}
+	for _, e := range order {
+		if len(next[e[0]]) == 0 {
+			continue
+		}
+		path := []corner{e[0]}
+		index := map[corner]int{e[0]: 0}
+		p := e[0]
+		for len(next[p]) > 0 {
+			qs := next[p]
+			q := qs[0]
+			next[p] = qs[1:]
+			if j, ok := index[q]; ok {
+				emit(path[j:])
+				for _, c := range path[j+1:] {
+					delete(index, c)
+				}
+				path = path[:j+1]
+			} else {
+				index[q] = len(path)
+				path = append(path, q)
+			}
+			p = q
+		}
+	}
+	return rings
+}
@@ -0,0 +1,92 @@
+package delaunay
+
+import "math"
+
+// LargestEmptyCircle returns the center and radius of the largest circle
+// that contains none of the triangulated points and whose center lies inside
+// the provided polygon, or inside the convex hull if polygon is nil. The
+// center is either a Voronoi vertex inside the polygon, an intersection of a
+// Voronoi edge with the polygon boundary, or a polygon vertex.
+func (t *Triangulation) LargestEmptyCircle(polygon []Point) (Point, float64) {
+	if polygon == nil {
+		polygon = t.ConvexHull
+	}
+	points := t.Points
+	ts := t.Triangles
+	if len(ts) == 0 || len(polygon) == 0 {
+		return Point{}, 0
+	}
+
+	var best Point
+	bestRadius := -1.0
+	try := func(p Point, r float64) {
+		if math.IsNaN(p.X) || math.IsNaN(p.Y) || math.IsInf(p.X, 0) || math.IsInf(p.Y, 0) {
+			return
+		}
+		if r > bestRadius {
+			best = p
+			bestRadius = r
+		}
+	}
+
+	// voronoi vertices inside the polygon; the empty circle around each is
+	// the circumcircle of its triangle
+	centers := make([]Point, len(ts)/3)
+	for i := range centers {
+		a, b, c := points[ts[i*3]], points[ts[i*3+1]], points[ts[i*3+2]]
+		centers[i] = circumcenter(a, b, c)
+		if polygonContains(polygon, centers[i]) {
+			try(centers[i], math.Sqrt(circumradius(a, b, c)))
+		}
+	}
+
+	// polygon vertices
+	for _, p := range polygon {
+		try(p, points[t.Nearest(p)].distance(p))
+	}
+
+	// intersections of voronoi edges with the polygon boundary; the edges
+	// dual to hull edges are rays pointing away from the hull, and every
+	// point on an edge is nearest to the two points of its dual edge
+	for i, h := range t.Halfedges {
+		if i < h {
+			continue
+		}
+		a := centers[i/3]
+		var d Point
+		ray := h < 0
+		if ray {
+			p := points[ts[i]]
+			q := points[ts[nextHalfedge(i)]]
+			d = Point{p.Y - q.Y, q.X - p.X}
+		} else {
+			d = centers[h/3].sub(a)
+		}
+		for j, p := range polygon {
+			q := polygon[(j+1)%len(polygon)]
+			if s, ok := intersectSegment(a, d, p, q, ray); ok {
+				x := a.add(d.scale(s))
+				try(x, x.distance(points[ts[i]]))
+			}
+		}
+	}
+
+	return best, bestRadius
+}
+
+// intersectSegment intersects the segment (or ray) a + s*d with the segment
+// pq and returns s
+func intersectSegment(a, d, p, q Point, ray bool) (float64, bool) {
+	e := q.sub(p)
+	den := d.X*e.Y - d.Y*e.X
+	if den == 0 {
+		return 0, false
+	}
+	f := p.sub(a)
+	s := (f.X*e.Y - f.Y*e.X) / den
+	u := (f.X*d.Y - f.Y*d.X) / den
+	if s < 0 || (!ray && s > 1) || u < 0 || u > 1 {
+		return 0, false
+	}
+	return s, true
+}
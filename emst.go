@@ -0,0 +1,48 @@
+package delaunay
+
+import "sort"
+
+// edges returns every edge of the triangulation once, as pairs of point
+// indices
+func (t *Triangulation) edges() [][2]int {
+	ts := t.Triangles
+	var result [][2]int
+	for i, h := range t.Halfedges {
+		if i > h {
+			result = append(result, [2]int{ts[i], ts[nextHalfedge(i)]})
+		}
+	}
+	return result
+}
+
+// EMST returns the Euclidean minimum spanning tree of the triangulated
+// points, which is a subgraph of the Delaunay triangulation, as pairs of
+// point indices along with the total length of the tree.
+func (t *Triangulation) EMST() ([][2]int, float64) {
+	points := t.Points
+	edges := t.edges()
+	lengths := make([]float64, len(edges))
+	for i, e := range edges {
+		lengths[i] = points[e[0]].distance(points[e[1]])
+	}
+	ids := make([]int, len(edges))
+	for i := range ids {
+		ids[i] = i
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return lengths[ids[i]] < lengths[ids[j]]
+	})
+
+	// kruskal's algorithm
+	var result [][2]int
+	var total float64
+	uf := newUnionFind(len(points))
+	for _, i := range ids {
+		e := edges[i]
+		if uf.union(e[0], e[1]) {
+			result = append(result, e)
+			total += lengths[i]
+		}
+	}
+	return result, total
+}
@@ -0,0 +1,197 @@
+package delaunay
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+type geoJSONObject struct {
+	Type        string                 `json:"type"`
+	Features    []*geoJSONObject       `json:"features"`
+	Geometry    *geoJSONObject         `json:"geometry"`
+	Geometries  []*geoJSONObject       `json:"geometries"`
+	Coordinates json.RawMessage        `json:"coordinates"`
+	Properties  map[string]interface{} `json:"properties"`
+}
+
+type geoJSONFeature struct {
+	Type       string                 `json:"type"`
+	Geometry   geoJSONGeometry        `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type geoJSONGeometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+type geoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+// ReadGeoJSON reads the points of a GeoJSON FeatureCollection, Feature or
+// geometry made of Point and MultiPoint geometries (possibly nested in
+// GeometryCollections). Along with each point, it returns the properties of
+// the feature it belongs to, which are shared by the points of a MultiPoint
+// and nil for bare geometries. Features with a null geometry are skipped.
+func ReadGeoJSON(r io.Reader) ([]Point, []map[string]interface{}, error) {
+	var object geoJSONObject
+	if err := json.NewDecoder(r).Decode(&object); err != nil {
+		return nil, nil, err
+	}
+	var points []Point
+	var properties []map[string]interface{}
+	var read func(o *geoJSONObject, props map[string]interface{}) error
+	read = func(o *geoJSONObject, props map[string]interface{}) error {
+		if o == nil {
+			return nil
+		}
+		switch o.Type {
+		case "FeatureCollection":
+			for _, f := range o.Features {
+				if err := read(f, nil); err != nil {
+					return err
+				}
+			}
+		case "Feature":
+			return read(o.Geometry, o.Properties)
+		case "GeometryCollection":
+			for _, g := range o.Geometries {
+				if err := read(g, props); err != nil {
+					return err
+				}
+			}
+		case "Point":
+			var c []float64
+			if err := json.Unmarshal(o.Coordinates, &c); err != nil {
+				return err
+			}
+			if len(c) < 2 {
+				return fmt.Errorf("invalid GeoJSON position")
+			}
+			points = append(points, Point{c[0], c[1]})
+			properties = append(properties, props)
+		case "MultiPoint":
+			var cs [][]float64
+			if err := json.Unmarshal(o.Coordinates, &cs); err != nil {
+				return err
+			}
+			for _, c := range cs {
+				if len(c) < 2 {
+					return fmt.Errorf("invalid GeoJSON position")
+				}
+				points = append(points, Point{c[0], c[1]})
+				properties = append(properties, props)
+			}
+		default:
+			return fmt.Errorf("unsupported GeoJSON type: %q", o.Type)
+		}
+		return nil
+	}
+	if err := read(&object, nil); err != nil {
+		return nil, nil, err
+	}
+	return points, properties, nil
+}
+
+// geoJSONRing converts a polygon into a closed GeoJSON linear ring
+func geoJSONRing(polygon []Point) [][]float64 {
+	ring := make([][]float64, 0, len(polygon)+1)
+	for _, p := range polygon {
+		ring = append(ring, []float64{p.X, p.Y})
+	}
+	return append(ring, ring[0])
+}
+
+func writeGeoJSON(w io.Writer, features []geoJSONFeature) error {
+	if features == nil {
+		features = []geoJSONFeature{}
+	}
+	return json.NewEncoder(w).Encode(geoJSONFeatureCollection{"FeatureCollection", features})
+}
+
+// WriteGeoJSON writes the triangles as a GeoJSON FeatureCollection of
+// counter-clockwise Polygons. Each feature has the properties "index" (the
+// index of the triangle), "area" and "points" (the indices of its points).
+func (t *Triangulation) WriteGeoJSON(w io.Writer) error {
+	polygons := t.polygons()
+	features := make([]geoJSONFeature, 0, len(polygons))
+	for i, polygon := range polygons {
+		features = append(features, geoJSONFeature{
+			Type:     "Feature",
+			Geometry: geoJSONGeometry{"Polygon", [][][]float64{geoJSONRing(polygon)}},
+			Properties: map[string]interface{}{
+				"index":  i,
+				"area":   polygonArea(polygon),
+				"points": t.corners(i),
+			},
+		})
+	}
+	return writeGeoJSON(w, features)
+}
+
+// WriteHullGeoJSON writes the convex hull as a GeoJSON FeatureCollection
+// holding a single Polygon, with its "area" and "perimeter" as properties.
+func (t *Triangulation) WriteHullGeoJSON(w io.Writer) error {
+	var features []geoJSONFeature
+	if len(t.ConvexHull) > 0 {
+		features = append(features, geoJSONFeature{
+			Type:     "Feature",
+			Geometry: geoJSONGeometry{"Polygon", [][][]float64{geoJSONRing(t.ConvexHull)}},
+			Properties: map[string]interface{}{
+				"area":      polygonArea(t.ConvexHull),
+				"perimeter": polygonPerimeter(t.ConvexHull),
+			},
+		})
+	}
+	return writeGeoJSON(w, features)
+}
+
+// WritePointsGeoJSON writes the points as a GeoJSON FeatureCollection of
+// Points. properties may be nil; otherwise it holds the properties of each
+// point, as returned by ReadGeoJSON. The "index" of each point is added to
+// a copy of its properties.
+func (t *Triangulation) WritePointsGeoJSON(w io.Writer, properties []map[string]interface{}) error {
+	features := make([]geoJSONFeature, len(t.Points))
+	for i, p := range t.Points {
+		features[i] = geoJSONFeature{
+			Type:       "Feature",
+			Geometry:   geoJSONGeometry{"Point", []float64{p.X, p.Y}},
+			Properties: geoJSONProperties(properties, i),
+		}
+	}
+	return writeGeoJSON(w, features)
+}
+
+// WriteVoronoiGeoJSON writes the Voronoi cells, clipped to the rectangle
+// [min, max], as a GeoJSON FeatureCollection of Polygons. properties may be
+// nil; otherwise each cell carries a copy of the properties of its point,
+// like in WritePointsGeoJSON. Points without a cell are skipped.
+func (t *Triangulation) WriteVoronoiGeoJSON(w io.Writer, min, max Point, properties []map[string]interface{}) error {
+	var features []geoJSONFeature
+	for i, cell := range t.VoronoiCells(min, max) {
+		if len(cell) == 0 {
+			continue
+		}
+		features = append(features, geoJSONFeature{
+			Type:       "Feature",
+			Geometry:   geoJSONGeometry{"Polygon", [][][]float64{geoJSONRing(cell)}},
+			Properties: geoJSONProperties(properties, i),
+		})
+	}
+	return writeGeoJSON(w, features)
+}
+
+// geoJSONProperties copies the properties of point i and adds its index
+func geoJSONProperties(properties []map[string]interface{}, i int) map[string]interface{} {
+	result := map[string]interface{}{}
+	if i < len(properties) {
+		for k, v := range properties[i] {
+			result[k] = v
+		}
+	}
+	result["index"] = i
+	return result
+}
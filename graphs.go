@@ -0,0 +1,137 @@
+package delaunay
+
+// GabrielGraph returns the edges of the Gabriel graph of the triangulated
+// points, as pairs of point indices. An edge belongs to the Gabriel graph if
+// the circle having it as a diameter contains no other points; only the
+// opposite points of the one or two adjacent triangles need to be checked.
+func (t *Triangulation) GabrielGraph() [][2]int {
+	points := t.Points
+	ts := t.Triangles
+	inside := func(a, b, c Point) bool {
+		// c lies strictly inside the circle with diameter ab
+		return a.sub(c).dot(b.sub(c)) < 0
+	}
+	var result [][2]int
+	for i, h := range t.Halfedges {
+		if i > h {
+			a := points[ts[i]]
+			b := points[ts[nextHalfedge(i)]]
+			c := points[ts[prevHalfedge(i)]]
+			if !inside(a, b, c) && (h < 0 || !inside(a, b, points[ts[prevHalfedge(h)]])) {
+				result = append(result, [2]int{ts[i], ts[nextHalfedge(i)]})
+			}
+		}
+	}
+	return result
+}
+
+// urquhart reports for each halfedge whether its edge is kept in the
+// Urquhart graph, which removes the longest edge of every triangle
+func (t *Triangulation) urquhart() []bool {
+	points := t.Points
+	ts := t.Triangles
+	keep := make([]bool, len(ts))
+	for i := range keep {
+		keep[i] = true
+	}
+	for i := 0; i < len(ts); i += 3 {
+		longest := -1
+		var maxLength float64
+		for e := i; e < i+3; e++ {
+			d := points[ts[e]].squaredDistance(points[ts[nextHalfedge(e)]])
+			if longest < 0 || d > maxLength {
+				longest = e
+				maxLength = d
+			}
+		}
+		keep[longest] = false
+		if h := t.Halfedges[longest]; h >= 0 {
+			keep[h] = false
+		}
+	}
+	return keep
+}
+
+// UrquhartGraph returns the edges of the Urquhart graph of the triangulated
+// points, as pairs of point indices. It is the Delaunay triangulation with
+// the longest edge of every triangle removed.
+func (t *Triangulation) UrquhartGraph() [][2]int {
+	ts := t.Triangles
+	keep := t.urquhart()
+	var result [][2]int
+	for i, h := range t.Halfedges {
+		if i > h && keep[i] {
+			result = append(result, [2]int{ts[i], ts[nextHalfedge(i)]})
+		}
+	}
+	return result
+}
+
+// RelativeNeighborhoodGraph returns the edges of the relative neighborhood
+// graph of the triangulated points, as pairs of point indices. An edge ab
+// belongs to the graph if no other point c is closer to both a and b than
+// they are to each other. The graph is a subgraph of the Urquhart graph, so
+// only its edges are checked. Every point in the lune of ab lies within
+// distance |ab| of a, and the Delaunay triangulation of the points inside
+// any circle is connected, so those points are found by walking outwards
+// from a. This visits few points for each edge on typical inputs, but it
+// takes quadratic time in the worst case, when many edges have many points
+// within their length of a, like for a point at the center of a circle of
+// points.
+func (t *Triangulation) RelativeNeighborhoodGraph() [][2]int {
+	points := t.Points
+	ts := t.Triangles
+
+	// find one outgoing halfedge for each point
+	edges := make([]int, len(points))
+	for e, i := range ts {
+		edges[i] = e
+	}
+
+	mark := make([]int, len(points))
+	stamp := 0
+	var stack []int
+	empty := func(a, b int) bool {
+		d := points[a].squaredDistance(points[b])
+		stamp++
+		mark[a] = stamp
+		stack = append(stack[:0], edges[a])
+		for len(stack) > 0 {
+			e := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			found := false
+			t.visitNeighbors(e, func(c, e int) {
+				if found || mark[c] == stamp {
+					return
+				}
+				mark[c] = stamp
+				dc := points[a].squaredDistance(points[c])
+				if dc > d {
+					return
+				}
+				if c != b && dc < d && points[b].squaredDistance(points[c]) < d {
+					found = true
+					return
+				}
+				stack = append(stack, e)
+			})
+			if found {
+				return false
+			}
+		}
+		return true
+	}
+
+	keep := t.urquhart()
+	var result [][2]int
+	for i, h := range t.Halfedges {
+		if i > h && keep[i] {
+			a := ts[i]
+			b := ts[nextHalfedge(i)]
+			if empty(a, b) {
+				result = append(result, [2]int{a, b})
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,157 @@
+package delaunay
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+)
+
+// TriangulateHeightmap builds a triangulated irregular network approximating
+// the heightmap, which has width x height samples stored row by row, using
+// greedy insertion: starting from the four corners, it repeatedly inserts
+// the sample with the largest vertical error relative to the current
+// Delaunay triangulation. It stops once the largest error is at most
+// maxError, or once the triangulation has maxPoints points if maxPoints is
+// positive. Points are in sample coordinates; the elevation of each point is
+// returned along with the triangulation.
+func TriangulateHeightmap(width, height int, data []float64, maxError float64, maxPoints int) (*Triangulation, []float64, error) {
+	if width < 2 || height < 2 {
+		return nil, nil, fmt.Errorf("heightmap must be at least 2x2")
+	}
+	if len(data) != width*height {
+		return nil, nil, fmt.Errorf("expected %d samples, got %d", width*height, len(data))
+	}
+
+	h := &heightmapTriangulator{width: width, height: height, data: data}
+	w := float64(width - 1)
+	hh := float64(height - 1)
+	h.addPoint(0, 0)
+	h.addPoint(w, 0)
+	h.addPoint(w, hh)
+	h.addPoint(0, hh)
+
+	t := newRegularTriangulator(h.points, nil)
+	if orient2d(h.points[0], h.points[1], h.points[2]) > 0 {
+		t.init(0, 1, 2)
+	} else {
+		t.init(0, 2, 1)
+	}
+	t.insert(3)
+	for i := range t.dead {
+		h.update(t, i)
+	}
+
+	for h.queue.Len() > 0 {
+		if maxPoints > 0 && len(h.points) >= maxPoints {
+			break
+		}
+		c := heap.Pop(&h.queue).(heightmapCandidate)
+		if t.dead[c.t] || t.triangles[c.t*3] != c.v[0] ||
+			t.triangles[c.t*3+1] != c.v[1] || t.triangles[c.t*3+2] != c.v[2] {
+			// the triangle has since been replaced
+			continue
+		}
+		if c.err <= maxError {
+			break
+		}
+		h.addPoint(c.p.X, c.p.Y)
+		t.points = h.points
+		if !t.insert(len(h.points) - 1) {
+			// the sample could not be inserted; drop it and queue the next
+			// best sample of the triangle instead
+			h.points = h.points[:len(h.points)-1]
+			h.elevations = h.elevations[:len(h.elevations)-1]
+			t.points = h.points
+			if h.rejected == nil {
+				h.rejected = make(map[Point]bool)
+			}
+			h.rejected[c.p] = true
+			h.update(t, c.t)
+			continue
+		}
+		for _, i := range t.created {
+			h.update(t, i)
+		}
+	}
+
+	triangles, halfedges := t.finite()
+	result := &Triangulation{h.points, t.convexHull(), triangles, halfedges}
+	return result, h.elevations, nil
+}
+
+type heightmapTriangulator struct {
+	width, height int
+	data          []float64
+	points        []Point
+	elevations    []float64
+	queue         heightmapQueue
+	rejected      map[Point]bool // samples that could not be inserted
+}
+
+func (h *heightmapTriangulator) addPoint(x, y float64) {
+	h.points = append(h.points, Point{x, y})
+	h.elevations = append(h.elevations, h.data[int(y)*h.width+int(x)])
+}
+
+// update finds the sample with the largest error inside of triangle i and
+// queues it as a candidate for insertion
+func (h *heightmapTriangulator) update(t *regularTriangulator, i int) {
+	if t.dead[i] || t.ghostVertex(i) >= 0 {
+		return
+	}
+	var v [3]int
+	copy(v[:], t.triangles[i*3:i*3+3])
+	a, b, c := h.points[v[0]], h.points[v[1]], h.points[v[2]]
+	za, zb, zc := h.elevations[v[0]], h.elevations[v[1]], h.elevations[v[2]]
+
+	x0 := int(math.Min(a.X, math.Min(b.X, c.X)))
+	y0 := int(math.Min(a.Y, math.Min(b.Y, c.Y)))
+	x1 := int(math.Max(a.X, math.Max(b.X, c.X)))
+	y1 := int(math.Max(a.Y, math.Max(b.Y, c.Y)))
+
+	d := area(a, b, c)
+	best := heightmapCandidate{t: i, v: v, err: -1}
+	for y := y0; y <= y1; y++ {
+		for x := x0; x <= x1; x++ {
+			p := Point{float64(x), float64(y)}
+
+			// barycentric coordinates
+			wa := area(p, b, c) / d
+			wb := area(a, p, c) / d
+			wc := area(a, b, p) / d
+			if wa < 0 || wb < 0 || wc < 0 || h.rejected[p] {
+				continue
+			}
+			z := wa*za + wb*zb + wc*zc
+			e := math.Abs(h.data[y*h.width+x] - z)
+			if e > best.err {
+				best.p = p
+				best.err = e
+			}
+		}
+	}
+	if best.err > 0 {
+		heap.Push(&h.queue, best)
+	}
+}
+
+type heightmapCandidate struct {
+	t   int
+	v   [3]int
+	p   Point
+	err float64
+}
+
+type heightmapQueue []heightmapCandidate
+
+func (q heightmapQueue) Len() int            { return len(q) }
+func (q heightmapQueue) Less(i, j int) bool  { return q[i].err > q[j].err }
+func (q heightmapQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *heightmapQueue) Push(x interface{}) { *q = append(*q, x.(heightmapCandidate)) }
+
+func (q *heightmapQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
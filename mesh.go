@@ -0,0 +1,39 @@
+package delaunay
+
+import "fmt"
+
+// newMeshTriangulation builds a Triangulation from triangles read from a
+// mesh file, reordering each triangle to wind clockwise like the
+// triangulator's output and recomputing the halfedges and convex hull
+func newMeshTriangulation(points []Point, triangles []int) (*Triangulation, error) {
+	for _, i := range triangles {
+		if i < 0 || i >= len(points) {
+			return nil, fmt.Errorf("point index %d out of range", i)
+		}
+	}
+	for i := 0; i < len(triangles); i += 3 {
+		a := points[triangles[i]]
+		b := points[triangles[i+1]]
+		c := points[triangles[i+2]]
+		if area(a, b, c) < 0 {
+			triangles[i+1], triangles[i+2] = triangles[i+2], triangles[i+1]
+		}
+	}
+	halfedges := computeHalfedges(triangles)
+	return &Triangulation{points, ConvexHull(points), triangles, halfedges}, nil
+}
+
+// corners returns the points of triangle i in counter-clockwise order, which
+// the file formats expect; Triangles winds clockwise
+func (t *Triangulation) corners(i int) [3]int {
+	ts := t.Triangles
+	return [3]int{ts[i*3], ts[i*3+2], ts[i*3+1]}
+}
+
+// fan splits a polygon face into triangles around its first point
+func fan(triangles []int, face []int) []int {
+	for j := 2; j < len(face); j++ {
+		triangles = append(triangles, face[0], face[j-1], face[j])
+	}
+	return triangles
+}
@@ -0,0 +1,201 @@
+package delaunay
+
+import "container/heap"
+
+// Locate returns the index of the triangle that contains p, such that the
+// triangle's points are Triangles[i*3], Triangles[i*3+1] and
+// Triangles[i*3+2], or -1 if p lies outside of the convex hull or is not
+// finite. It walks
+// across the triangulation starting from the first triangle; use LocateFrom
+// to locate a sequence of nearby points quickly.
+func (t *Triangulation) Locate(p Point) int {
+	return t.LocateFrom(p, 0)
+}
+
+// LocateFrom is like Locate, but starts walking from triangle start, which
+// is typically the result for a previous point close to p. The walk takes
+// time proportional to the number of triangles between start and p.
+func (t *Triangulation) LocateFrom(p Point, start int) int {
+	if len(t.Triangles) == 0 || !p.finite() {
+		return -1
+	}
+	if start < 0 || start >= len(t.Triangles)/3 {
+		start = 0
+	}
+	i, ok := t.locate(p, start)
+	if !ok {
+		return -1
+	}
+	return i
+}
+
+// locate walks from triangle i towards p and returns the triangle that
+// contains it, or the hull triangle where the walk left the triangulation
+func (t *Triangulation) locate(p Point, i int) (int, bool) {
+	points := t.Points
+	ts := t.Triangles
+	hs := t.Halfedges
+	for steps := 0; steps < len(ts); steps++ {
+		moved := false
+		for k := 0; k < 3; k++ {
+			// vary the order of the edges to avoid walking in circles
+			e := i*3 + (k+steps)%3
+			a := points[ts[e]]
+			b := points[ts[nextHalfedge(e)]]
+			if orient2d(a, b, p) < 0 {
+				if hs[e] < 0 {
+					return i, false
+				}
+				i = hs[e] / 3
+				moved = true
+				break
+			}
+		}
+		if !moved {
+			return i, true
+		}
+	}
+
+	// the walk did not terminate; fall back to checking every triangle
+	for i := 0; i < len(ts); i += 3 {
+		a := points[ts[i+0]]
+		b := points[ts[i+1]]
+		c := points[ts[i+2]]
+		if orient2d(a, b, p) >= 0 && orient2d(b, c, p) >= 0 && orient2d(c, a, p) >= 0 {
+			return i / 3, true
+		}
+	}
+	return i, false
+}
+
+// visitNeighbors calls fn for every neighbor of the start point of halfedge
+// e, along with a halfedge that starts at that neighbor
+func (t *Triangulation) visitNeighbors(e int, fn func(i, e int)) {
+	ts := t.Triangles
+	hs := t.Halfedges
+	start := e
+	for {
+		n := nextHalfedge(e)
+		fn(ts[n], n)
+		p := prevHalfedge(e)
+		e = hs[p]
+		if e == start {
+			return
+		}
+		if e < 0 {
+			fn(ts[p], p)
+			break
+		}
+	}
+
+	// the point is on the convex hull; walk around the other way
+	e = start
+	for hs[e] >= 0 {
+		e = nextHalfedge(hs[e])
+		n := nextHalfedge(e)
+		fn(ts[n], n)
+	}
+}
+
+// nearest returns the index of the point closest to p and a halfedge that
+// starts at it, or -1 if there are no triangles or p is not finite
+func (t *Triangulation) nearest(p Point) (int, int) {
+	if len(t.Triangles) == 0 || !p.finite() {
+		return -1, -1
+	}
+
+	// greedily walk towards p from a point of the triangle containing it;
+	// in a Delaunay triangulation this always ends at the nearest point
+	i, _ := t.locate(p, 0)
+	e := i * 3
+	for {
+		best := e
+		bestDist := t.Points[t.Triangles[e]].squaredDistance(p)
+		t.visitNeighbors(e, func(i, h int) {
+			if d := t.Points[i].squaredDistance(p); d < bestDist {
+				best = h
+				bestDist = d
+			}
+		})
+		if best == e {
+			return t.Triangles[e], e
+		}
+		e = best
+	}
+}
+
+// Nearest returns the index of the triangulated point closest to p, or -1 if
+// there are no triangles or p has NaN or infinite coordinates. Points that are not part of the triangulation
+// (duplicates) are never returned.
+func (t *Triangulation) Nearest(p Point) int {
+	i, _ := t.nearest(p)
+	return i
+}
+
+// KNearest returns the indices of the k triangulated points closest to p,
+// ordered by their distance to p, or nil if k is not positive.
+func (t *Triangulation) KNearest(p Point, k int) []int {
+	if k <= 0 {
+		return nil
+	}
+	return t.search(p, k, infinity)
+}
+
+// WithinRadius returns the indices of the triangulated points within
+// distance r of p, ordered by their distance to p, or nil if r is negative.
+func (t *Triangulation) WithinRadius(p Point, r float64) []int {
+	if !(r >= 0) {
+		return nil
+	}
+	return t.search(p, len(t.Points), r*r)
+}
+
+// search expands outwards from the point nearest to p in order of distance,
+// which visits the points in order because the Delaunay triangulation of the
+// points inside any circle is connected; it stops after k points or at the
+// first point farther than maxDist, a squared distance
+func (t *Triangulation) search(p Point, k int, maxDist float64) []int {
+	var result []int
+	i, e := t.nearest(p)
+	if i < 0 {
+		return result
+	}
+	visited := map[int]bool{i: true}
+	queue := &searchQueue{{i, e, t.Points[i].squaredDistance(p)}}
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(searchItem)
+		if item.dist > maxDist {
+			break
+		}
+		result = append(result, item.i)
+		if len(result) == k {
+			break
+		}
+		t.visitNeighbors(item.e, func(i, e int) {
+			if !visited[i] {
+				visited[i] = true
+				heap.Push(queue, searchItem{i, e, t.Points[i].squaredDistance(p)})
+			}
+		})
+	}
+	return result
+}
+
+type searchItem struct {
+	i, e int
+	dist float64
+}
+
+type searchQueue []searchItem
+
+func (q searchQueue) Len() int            { return len(q) }
+func (q searchQueue) Less(i, j int) bool  { return q[i].dist < q[j].dist }
+func (q searchQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *searchQueue) Push(x interface{}) { *q = append(*q, x.(searchItem)) }
+
+func (q *searchQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
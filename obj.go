@@ -0,0 +1,148 @@
+package delaunay
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WriteOBJ writes the triangulation as a Wavefront OBJ mesh. z, which may be
+// nil, holds the elevation of each point. values, which may also be nil,
+// holds a scalar per point that is stored as the u texture coordinate. Faces
+// are written counter-clockwise so that their normals point up.
+func (t *Triangulation) WriteOBJ(w io.Writer, z, values []float64) error {
+	if z != nil && len(z) != len(t.Points) {
+		return fmt.Errorf("expected %d elevations, got %d", len(t.Points), len(z))
+	}
+	if values != nil && len(values) != len(t.Points) {
+		return fmt.Errorf("expected %d values, got %d", len(t.Points), len(values))
+	}
+	bw := bufio.NewWriter(w)
+	for i, p := range t.Points {
+		var pz float64
+		if z != nil {
+			pz = z[i]
+		}
+		fmt.Fprintf(bw, "v %s %s %s\n", formatFloat(p.X), formatFloat(p.Y), formatFloat(pz))
+	}
+	for _, v := range values {
+		fmt.Fprintf(bw, "vt %s 0\n", formatFloat(v))
+	}
+	for i := 0; i < len(t.Triangles)/3; i++ {
+		corners := t.corners(i)
+		a, b, c := corners[0]+1, corners[1]+1, corners[2]+1
+		if values != nil {
+			fmt.Fprintf(bw, "f %d/%d %d/%d %d/%d\n", a, a, b, b, c, c)
+		} else {
+			fmt.Fprintf(bw, "f %d %d %d\n", a, b, c)
+		}
+	}
+	return bw.Flush()
+}
+
+// ReadOBJ reads a Wavefront OBJ mesh written by WriteOBJ or another program,
+// returning the triangulation along with the elevation of each point and the
+// values stored as texture coordinates, which are nil if the file has none.
+// Polygon faces are split into triangles, and Halfedges and ConvexHull are
+// recomputed. The mesh is projected onto the xy plane, so it should be a
+// height field for the result to be a valid triangulation.
+func ReadOBJ(r io.Reader) (*Triangulation, []float64, []float64, error) {
+	var points []Point
+	var z, texcoords, values []float64
+	var triangles, face []int
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "v":
+			if len(fields) < 3 {
+				return nil, nil, nil, fmt.Errorf("line %d: invalid vertex", line)
+			}
+			var c [3]float64
+			for j := 1; j < len(fields) && j <= 3; j++ {
+				f, err := strconv.ParseFloat(fields[j], 64)
+				if err != nil {
+					return nil, nil, nil, fmt.Errorf("line %d: %v", line, err)
+				}
+				c[j-1] = f
+			}
+			points = append(points, Point{c[0], c[1]})
+			z = append(z, c[2])
+		case "vt":
+			if len(fields) < 2 {
+				return nil, nil, nil, fmt.Errorf("line %d: invalid texture coordinate", line)
+			}
+			f, err := strconv.ParseFloat(fields[1], 64)
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("line %d: %v", line, err)
+			}
+			texcoords = append(texcoords, f)
+		case "f":
+			face = face[:0]
+			for _, field := range fields[1:] {
+				parts := strings.Split(field, "/")
+				i, err := objIndex(parts[0], len(points))
+				if err != nil {
+					return nil, nil, nil, fmt.Errorf("line %d: %v", line, err)
+				}
+				face = append(face, i)
+				if len(parts) > 1 && parts[1] != "" {
+					j, err := objIndex(parts[1], len(texcoords))
+					if err != nil {
+						return nil, nil, nil, fmt.Errorf("line %d: %v", line, err)
+					}
+					if values == nil {
+						values = make([]float64, 0, len(points))
+					}
+					for len(values) <= i {
+						values = append(values, 0)
+					}
+					values[i] = texcoords[j]
+				}
+			}
+			if len(face) < 3 {
+				return nil, nil, nil, fmt.Errorf("line %d: invalid face", line)
+			}
+			triangles = fan(triangles, face)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, nil, err
+	}
+	if values != nil {
+		for len(values) < len(points) {
+			values = append(values, 0)
+		}
+	}
+	t, err := newMeshTriangulation(points, triangles)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return t, z, values, nil
+}
+
+// objIndex converts a one-based, possibly negative (relative) OBJ index
+func objIndex(s string, n int) (int, error) {
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err
+	}
+	if i < 0 {
+		i += n
+	} else {
+		i--
+	}
+	if i < 0 || i >= n {
+		return 0, fmt.Errorf("index %s out of range", s)
+	}
+	return i, nil
+}
+
+func formatFloat(x float64) string {
+	return strconv.FormatFloat(x, 'g', -1, 64)
+}
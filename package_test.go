@@ -1,8 +1,17 @@
 package delaunay
 
 import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
 	"math"
 	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
 )
 
@@ -156,3 +165,1516 @@ func BenchmarkGrid(b *testing.B) {
 	points := grid(b.N, rnd)
 	Triangulate(points)
 }
+
+func TestSphere(t *testing.T) {
+	rnd := rand.New(rand.NewSource(99))
+	points := make([]Vector, 10000)
+	for i := range points {
+		points[i] = Vector{rnd.NormFloat64(), rnd.NormFloat64(), rnd.NormFloat64()}
+	}
+	tri, err := TriangulateSphere(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tri.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	for i, cell := range tri.VoronoiCells() {
+		if len(cell) < 3 {
+			t.Fatalf("point %d has an invalid voronoi cell", i)
+		}
+	}
+}
+
+func TestSphereLonLat(t *testing.T) {
+	var points []Point
+	for lat := -90; lat <= 90; lat += 15 {
+		for lon := -180; lon < 180; lon += 15 {
+			points = append(points, Point{float64(lon), float64(lat)})
+		}
+	}
+	tri, err := TriangulateLonLat(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tri.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func validate3D(t *testing.T, points []Vector) *Tetrahedralization {
+	tet, err := Tetrahedralize(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = tet.Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tet
+}
+
+func TestTetrahedralize(t *testing.T) {
+	rnd := rand.New(rand.NewSource(99))
+	points := make([]Vector, 10000)
+	for i := range points {
+		points[i] = Vector{rnd.Float64(), rnd.Float64(), rnd.Float64()}
+	}
+	validate3D(t, points)
+}
+
+func TestTetrahedralizeGrid(t *testing.T) {
+	var points []Vector
+	for z := 0; z < 5; z++ {
+		for y := 0; y < 5; y++ {
+			for x := 0; x < 5; x++ {
+				points = append(points, Vector{float64(x), float64(y), float64(z)})
+			}
+		}
+	}
+	points = append(points, points[:10]...)
+	tet := validate3D(t, points)
+
+	// the volumes of the tetrahedra should add up to the volume of the cube
+	var volume float64
+	ts := tet.Tetrahedra
+	for i := 0; i < len(ts); i += 4 {
+		a := points[ts[i+0]]
+		b := points[ts[i+1]]
+		c := points[ts[i+2]]
+		d := points[ts[i+3]]
+		volume += b.sub(a).cross(c.sub(a)).dot(d.sub(a)) / 6
+	}
+	if math.Abs(volume-64) > 1e-9 {
+		t.Fatalf("invalid total volume: %f", volume)
+	}
+}
+
+func TestTetrahedralizeDegenerate(t *testing.T) {
+	if _, err := Tetrahedralize([]Vector{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {1, 1, 0}}); err == nil {
+		t.Fatal("should have failed")
+	}
+}
+
+func TestWeighted(t *testing.T) {
+	rnd := rand.New(rand.NewSource(99))
+	points := uniform(1000, rnd)
+	weights := make([]float64, len(points))
+	for i := range weights {
+		weights[i] = rnd.Float64() * 0.002
+	}
+	tri, err := TriangulateWeighted(points, weights)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tri.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	// no lifted point may lie below the plane of any triangle
+	ts := tri.Triangles
+	used := make(map[int]bool)
+	for i := 0; i < len(ts); i += 3 {
+		a, b, c := ts[i], ts[i+1], ts[i+2]
+		used[a], used[b], used[c] = true, true, true
+		for j, p := range points {
+			if powerTest(points[a], points[b], points[c], p, weights[a], weights[b], weights[c], weights[j]) > 0 {
+				t.Fatalf("point %d conflicts with triangle %d", j, i/3)
+			}
+		}
+	}
+	if len(used) == len(points) {
+		t.Fatal("expected some redundant points")
+	}
+
+	// the power cells should partition the bounding rectangle
+	cells, err := PowerDiagram(points, weights, Point{0, 0}, Point{1, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var total float64
+	for i, cell := range cells {
+		if cell != nil && !used[i] {
+			t.Fatalf("redundant point %d has a cell", i)
+		}
+		total += polygonArea(cell)
+	}
+	if math.Abs(total-1) > 1e-9 {
+		t.Fatalf("power cells cover an area of %f", total)
+	}
+}
+
+func TestWeightedUnweighted(t *testing.T) {
+	rnd := rand.New(rand.NewSource(99))
+	points := normal(10000, rnd)
+	tri1 := validate(t, points)
+	tri2, err := TriangulateWeighted(points, make([]float64, len(points)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tri2.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if len(tri1.Triangles) != len(tri2.Triangles) {
+		t.Fatalf("triangle counts differ: %d, %d", len(tri1.Triangles), len(tri2.Triangles))
+	}
+}
+
+func TestPeriodic(t *testing.T) {
+	rnd := rand.New(rand.NewSource(99))
+	points := uniform(10000, rnd)
+	tri, err := TriangulatePeriodic(points, Point{0, 0}, Point{1, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tri.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	// sparse points outside of the domain
+	points = normal(20, rnd)
+	tri, err = TriangulatePeriodic(points, Point{0, 0}, Point{2, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tri.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	// tiny negative offsets wrap to min, not max
+	points = []Point{{-1e-17, 0.5}, {0.5, -1e-17}}
+	tri, err = TriangulatePeriodic(append(points, uniform(50, rnd)...), Point{0, 0}, Point{1, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tri.Points[0] != (Point{0, 0.5}) || tri.Points[1] != (Point{0.5, 0}) {
+		t.Fatalf("unexpected wrapped points: %v, %v", tri.Points[0], tri.Points[1])
+	}
+
+	// lattices and single points are cocircular in every translate
+	check := func(name string, points []Point, min, max Point, n int) {
+		tri, err := TriangulatePeriodic(points, min, max)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if err := tri.Validate(); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if len(tri.Triangles)/3 != 2*n {
+			t.Fatalf("%s: expected %d triangles, got %d", name, 2*n, len(tri.Triangles)/3)
+		}
+	}
+	points = nil
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 10; x++ {
+			points = append(points, Point{float64(x) / 10, float64(y) / 10})
+		}
+	}
+	check("lattice", points, Point{0, 0}, Point{1, 1}, 100)
+	points = nil
+	for y := 0; y < 5; y++ {
+		for x := 0; x < 7; x++ {
+			points = append(points, Point{float64(x)*0.3 + 0.05, float64(y)*0.2 - 0.1})
+		}
+	}
+	check("shifted lattice", points, Point{0, -0.1}, Point{2.1, 0.9}, 35)
+	check("single point", []Point{{0.3, 0.7}}, Point{0, 0}, Point{1, 1}, 1)
+
+	// near duplicates are found relative to the domain and across its
+	// boundary, but distinct points in a tiny domain are kept
+	check("near duplicates", append([]Point{{0.5, 0.1}, {0.5 + 1e-11, 0.9}, {0.5 + 2e-11, 0.1}}, uniform(20, rnd)...),
+		Point{0, 0}, Point{1, 1}, 22)
+	check("wrapped duplicates", append([]Point{{0, 0.5}, {1 - 1e-13, 0.5}}, uniform(20, rnd)...),
+		Point{0, 0}, Point{1, 1}, 21)
+	points = uniform(50, rnd)
+	for i := range points {
+		points[i] = points[i].scale(1e-6)
+	}
+	check("tiny domain", points, Point{0, 0}, Point{1e-6, 1e-6}, 50)
+}
+
+func TestAlphaShape(t *testing.T) {
+	// a grid with a hole in the middle
+	var points []Point
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 20; x++ {
+			if math.Hypot(float64(x)-9.5, float64(y)-9.5) < 4 {
+				continue
+			}
+			points = append(points, Point{float64(x), float64(y)})
+		}
+	}
+	tri := validate(t, points)
+
+	rings := tri.AlphaShape(1)
+	if len(rings) != 2 {
+		t.Fatalf("expected 2 rings, got %d", len(rings))
+	}
+	var outer, inner float64
+	for _, ring := range rings {
+		a := polygonArea(ring)
+		if a > 0 {
+			outer += a
+		} else {
+			inner += a
+		}
+	}
+	if outer != 19*19 || inner >= 0 {
+		t.Fatalf("invalid ring areas: %f, %f", outer, inner)
+	}
+
+	rings = tri.AlphaShape(infinity)
+	if len(rings) != 1 || polygonArea(rings[0]) != polygonArea(tri.ConvexHull) {
+		t.Fatal("alpha shape should match the convex hull")
+	}
+
+	alpha := tri.OptimalAlpha()
+	if math.Abs(alpha-math.Sqrt2/2) > 1e-9 {
+		t.Fatalf("unexpected optimal alpha: %f", alpha)
+	}
+}
+
+func TestEMST(t *testing.T) {
+	rnd := rand.New(rand.NewSource(99))
+	points := uniform(200, rnd)
+	tri := validate(t, points)
+	edges, total := tri.EMST()
+	if len(edges) != len(points)-1 {
+		t.Fatalf("expected %d edges, got %d", len(points)-1, len(edges))
+	}
+
+	// compare against prim's algorithm on the complete graph
+	dist := make([]float64, len(points))
+	done := make([]bool, len(points))
+	for i := range dist {
+		dist[i] = infinity
+	}
+	dist[0] = 0
+	var expected float64
+	for range points {
+		j := -1
+		for i := range points {
+			if !done[i] && (j < 0 || dist[i] < dist[j]) {
+				j = i
+			}
+		}
+		done[j] = true
+		expected += dist[j]
+		for i, p := range points {
+			dist[i] = math.Min(dist[i], p.distance(points[j]))
+		}
+	}
+	if math.Abs(total-expected) > 1e-9 {
+		t.Fatalf("invalid tree length: %f, expected %f", total, expected)
+	}
+}
+
+func TestProximityGraphs(t *testing.T) {
+	edgeSet := func(edges [][2]int) map[[2]int]bool {
+		result := make(map[[2]int]bool)
+		for _, e := range edges {
+			if e[0] > e[1] {
+				e[0], e[1] = e[1], e[0]
+			}
+			result[e] = true
+		}
+		return result
+	}
+
+	for seed := int64(0); seed < 50; seed++ {
+		rnd := rand.New(rand.NewSource(seed))
+		points := normal(400, rnd)
+		tri := validate(t, points)
+
+		// the graphs are subgraphs of the Delaunay triangulation, so compare
+		// against brute force definitions on its edges
+		delaunay := edgeSet(tri.edges())
+		gabriel := edgeSet(tri.GabrielGraph())
+		rng := edgeSet(tri.RelativeNeighborhoodGraph())
+		urquhart := edgeSet(tri.UrquhartGraph())
+		for _, graph := range []map[[2]int]bool{gabriel, rng, urquhart} {
+			for e := range graph {
+				if !delaunay[e] {
+					t.Fatalf("seed %d: edge %v is not a delaunay edge", seed, e)
+				}
+			}
+		}
+		for e := range delaunay {
+			a := points[e[0]]
+			b := points[e[1]]
+			d := a.squaredDistance(b)
+			isGabriel, isRNG := true, true
+			for k, c := range points {
+				if k == e[0] || k == e[1] {
+					continue
+				}
+				if a.squaredDistance(c)+b.squaredDistance(c) < d {
+					isGabriel = false
+				}
+				if a.squaredDistance(c) < d && b.squaredDistance(c) < d {
+					isRNG = false
+				}
+			}
+			if gabriel[e] != isGabriel {
+				t.Fatalf("seed %d: gabriel graph mismatch for edge %v", seed, e)
+			}
+			if rng[e] != isRNG {
+				t.Fatalf("seed %d: relative neighborhood graph mismatch for edge %v", seed, e)
+			}
+			if isRNG && !urquhart[e] {
+				t.Fatalf("seed %d: urquhart graph is missing edge %v", seed, e)
+			}
+		}
+	}
+}
+
+func TestCluster(t *testing.T) {
+	// two well separated blobs
+	rnd := rand.New(rand.NewSource(99))
+	points := uniform(500, rnd)
+	for i := 0; i < 250; i++ {
+		points[i].X += 5
+	}
+	tri := validate(t, points)
+
+	for _, c := range []*Clustering{tri.Cluster(1), tri.ClusterCount(2)} {
+		if len(c.Outlines) != 2 {
+			t.Fatalf("expected 2 clusters, got %d", len(c.Outlines))
+		}
+		for i, label := range c.Labels {
+			if (label == c.Labels[0]) != (i < 250) {
+				t.Fatalf("point %d is in the wrong cluster", i)
+			}
+		}
+		for _, rings := range c.Outlines {
+			if len(rings) == 0 {
+				t.Fatal("expected an outline for each cluster")
+			}
+		}
+	}
+
+	c := tri.ClusterCount(len(points))
+	if len(c.Outlines) != len(points) {
+		t.Fatalf("expected %d clusters, got %d", len(points), len(c.Outlines))
+	}
+}
+
+func TestNearest(t *testing.T) {
+	rnd := rand.New(rand.NewSource(99))
+	points := normal(2000, rnd)
+	tri := validate(t, points)
+	for n := 0; n < 100; n++ {
+		p := Point{rnd.NormFloat64() * 2, rnd.NormFloat64() * 2}
+
+		// brute force ordering by distance
+		ids := make([]int, len(points))
+		for i := range ids {
+			ids[i] = i
+		}
+		sort.Slice(ids, func(i, j int) bool {
+			return points[ids[i]].squaredDistance(p) < points[ids[j]].squaredDistance(p)
+		})
+
+		if i := tri.Nearest(p); i != ids[0] {
+			t.Fatalf("nearest point to %v is %d, expected %d", p, i, ids[0])
+		}
+		if !reflect.DeepEqual(tri.KNearest(p, 10), ids[:10]) {
+			t.Fatalf("invalid k nearest points to %v", p)
+		}
+		r := (points[ids[20]].distance(p) + points[ids[21]].distance(p)) / 2
+		if !reflect.DeepEqual(tri.WithinRadius(p, r), ids[:21]) {
+			t.Fatalf("invalid points within radius of %v", p)
+		}
+
+		i := tri.Locate(p)
+		inside := polygonContains(tri.ConvexHull, p)
+		if (i >= 0) != inside {
+			t.Fatalf("invalid location of %v", p)
+		}
+		if i >= 0 {
+			a := points[tri.Triangles[i*3+0]]
+			b := points[tri.Triangles[i*3+1]]
+			c := points[tri.Triangles[i*3+2]]
+			if orient2d(a, b, p) < 0 || orient2d(b, c, p) < 0 || orient2d(c, a, p) < 0 {
+				t.Fatalf("triangle %d does not contain %v", i, p)
+			}
+		}
+	}
+
+	// a coherent sequence of points located from the previous result
+	previous := 0
+	for k := 0; k <= 100; k++ {
+		p := Point{float64(k) / 100, 0.5}
+		i := tri.LocateFrom(p, previous)
+		if i != tri.Locate(p) && i >= 0 {
+			a := points[tri.Triangles[i*3+0]]
+			b := points[tri.Triangles[i*3+1]]
+			c := points[tri.Triangles[i*3+2]]
+			if orient2d(a, b, p) < 0 || orient2d(b, c, p) < 0 || orient2d(c, a, p) < 0 {
+				t.Fatalf("triangle %d does not contain %v", i, p)
+			}
+		}
+		if (i >= 0) != polygonContains(tri.ConvexHull, p) {
+			t.Fatalf("invalid location of %v", p)
+		}
+		if i >= 0 {
+			previous = i
+		}
+	}
+
+	// invalid queries return nothing
+	for _, p := range []Point{{math.NaN(), 0}, {0, math.Inf(1)}, {math.Inf(-1), math.NaN()}} {
+		if tri.Locate(p) != -1 || tri.Nearest(p) != -1 || tri.KNearest(p, 3) != nil || tri.WithinRadius(p, 1) != nil {
+			t.Fatalf("unexpected result for %v", p)
+		}
+	}
+	p := Point{0, 0}
+	if tri.KNearest(p, 0) != nil || tri.KNearest(p, -1) != nil || tri.WithinRadius(p, -0.2) != nil {
+		t.Fatal("expected no points for a non-positive k or a negative radius")
+	}
+	if len(tri.WithinRadius(p, 0.2)) == 0 || len(tri.KNearest(p, len(points)+1)) != len(points) {
+		t.Fatal("unexpected number of points")
+	}
+}
+
+func TestLargestEmptyCircle(t *testing.T) {
+	rnd := rand.New(rand.NewSource(99))
+	points := uniform(500, rnd)
+	tri := validate(t, points)
+	polygon := []Point{{0.2, 0.2}, {0.8, 0.2}, {0.8, 0.8}, {0.2, 0.8}}
+	for _, polygon := range [][]Point{nil, polygon} {
+		center, radius := tri.LargestEmptyCircle(polygon)
+		if polygon == nil {
+			polygon = tri.ConvexHull
+		}
+		if r := points[tri.Nearest(center)].distance(center); math.Abs(r-radius) > 1e-9 {
+			t.Fatalf("radius is %f, but the nearest point is %f away", radius, r)
+		}
+		for _, p := range points {
+			if p.distance(center) < radius-1e-9 {
+				t.Fatal("circle is not empty")
+			}
+		}
+
+		// no voronoi vertex inside the polygon does better
+		ts := tri.Triangles
+		for i := 0; i < len(ts); i += 3 {
+			p := circumcenter(points[ts[i]], points[ts[i+1]], points[ts[i+2]])
+			if !polygonContains(polygon, p) {
+				continue
+			}
+			if r := points[tri.Nearest(p)].distance(p); r > radius+1e-9 {
+				t.Fatalf("found a larger empty circle at %v: %f > %f", p, r, radius)
+			}
+		}
+
+		// no sampled center inside the polygon does better
+		for i := 0; i < 10000; i++ {
+			p := Point{rnd.Float64(), rnd.Float64()}
+			if !polygonContains(polygon, p) {
+				continue
+			}
+			if r := points[tri.Nearest(p)].distance(p); r > radius+1e-9 {
+				t.Fatalf("found a larger empty circle at %v: %f > %f", p, r, radius)
+			}
+		}
+	}
+}
+
+func TestContours(t *testing.T) {
+	rnd := rand.New(rand.NewSource(99))
+	points := uniform(5000, rnd)
+	tri := validate(t, points)
+	center := Point{0.5, 0.5}
+	values := make([]float64, len(points))
+	for i, p := range points {
+		values[i] = p.distance(center)
+	}
+
+	contours := tri.Contours(values, []float64{0.3})
+	if len(contours[0]) != 1 {
+		t.Fatalf("expected 1 line, got %d", len(contours[0]))
+	}
+	line := contours[0][0]
+	if line[0] != line[len(line)-1] {
+		t.Fatal("line should be closed")
+	}
+	for _, p := range line {
+		if math.Abs(p.distance(center)-0.3) > 0.01 {
+			t.Fatalf("point %v is not on the contour", p)
+		}
+	}
+	if polygonArea(line) > 0 {
+		t.Fatal("higher values should be on the left of the line")
+	}
+
+	xs := make([]float64, len(points))
+	for i, p := range points {
+		xs[i] = p.X
+	}
+	contours = tri.Contours(xs, []float64{0.5})
+	if len(contours[0]) != 1 {
+		t.Fatalf("expected 1 line, got %d", len(contours[0]))
+	}
+	line = contours[0][0]
+	if line[0] == line[len(line)-1] || line[0].Y < line[len(line)-1].Y {
+		t.Fatal("line should be open and run from top to bottom")
+	}
+
+	bands := tri.Isobands(values, []float64{0, 0.2, 0.4, 10})
+	if len(bands[0]) != 1 || len(bands[1]) != 2 {
+		t.Fatalf("unexpected ring counts: %d, %d", len(bands[0]), len(bands[1]))
+	}
+	var total float64
+	for _, rings := range bands {
+		for _, ring := range rings {
+			total += polygonArea(ring)
+		}
+	}
+	if math.Abs(total-polygonArea(tri.ConvexHull)) > 1e-9 {
+		t.Fatalf("isobands cover an area of %f", total)
+	}
+}
+
+func TestIsobandsTies(t *testing.T) {
+	// integer values with integer levels put many points exactly on a level
+	levels := []float64{0, 1, 2, 3, 4, 5}
+	for seed := int64(0); seed < 30; seed++ {
+		rnd := rand.New(rand.NewSource(seed))
+		points := uniform(2000, rnd)
+		tri := validate(t, points)
+		values := make([]float64, len(points))
+		for i := range values {
+			values[i] = math.Floor(rnd.Float64() * 5)
+		}
+		var total float64
+		for _, rings := range tri.Isobands(values, levels) {
+			for _, ring := range rings {
+				seen := make(map[Point]bool)
+				for _, p := range ring {
+					if seen[p] {
+						t.Fatalf("seed %d: ring visits %v twice", seed, p)
+					}
+					seen[p] = true
+				}
+				total += polygonArea(ring)
+			}
+		}
+		if math.Abs(total-polygonArea(tri.ConvexHull)) > 1e-9 {
+			t.Fatalf("seed %d: isobands cover an area of %f instead of %f",
+				seed, total, polygonArea(tri.ConvexHull))
+		}
+	}
+}
+
+func TestRasterize(t *testing.T) {
+	rnd := rand.New(rand.NewSource(99))
+	points := uniform(1000, rnd)
+	tri := validate(t, points)
+
+	// a linear field is reproduced exactly
+	values := make([]float64, len(points))
+	for i, p := range points {
+		values[i] = 2*p.X - 3*p.Y + 1
+	}
+	grid := Grid{Point{-0.1, -0.1}, 0.01, 120, 120}
+	nodata := math.NaN()
+	raster := tri.Rasterize(values, grid, nodata, 1)
+	parallel := tri.Rasterize(values, grid, nodata, 4)
+	for y := 0; y < grid.Height; y++ {
+		for x := 0; x < grid.Width; x++ {
+			i := y*grid.Width + x
+			p := Point{grid.Origin.X + (float64(x)+0.5)*grid.CellSize, grid.Origin.Y + (float64(y)+0.5)*grid.CellSize}
+			v := raster[i]
+			if math.IsNaN(v) {
+				if tri.Locate(p) >= 0 {
+					t.Fatalf("cell %d, %d should have a value", x, y)
+				}
+				continue
+			}
+			if math.Abs(v-(2*p.X-3*p.Y+1)) > 1e-9 {
+				t.Fatalf("invalid value for cell %d, %d: %f", x, y, v)
+			}
+			if parallel[i] != v {
+				t.Fatalf("parallel result differs for cell %d, %d", x, y)
+			}
+		}
+	}
+}
+
+func TestHeightmap(t *testing.T) {
+	const w, h = 65, 49
+	data := make([]float64, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			data[y*w+x] = math.Sin(float64(x)/10) * math.Cos(float64(y)/7)
+		}
+	}
+
+	tri, elevations, err := TriangulateHeightmap(w, h, data, 0.01, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tri.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if len(tri.Points) >= w*h/2 {
+		t.Fatalf("too many points: %d", len(tri.Points))
+	}
+
+	// cell centers of this grid are at the samples
+	grid := Grid{Point{-0.5, -0.5}, 1, w, h}
+	raster := tri.Rasterize(elevations, grid, math.NaN(), 1)
+	for i, z := range raster {
+		if math.IsNaN(z) || math.Abs(z-data[i]) > 0.01+1e-9 {
+			t.Fatalf("sample %d has an error of %f", i, math.Abs(z-data[i]))
+		}
+	}
+
+	tri, _, err = TriangulateHeightmap(w, h, data, 0, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tri.Points) != 100 {
+		t.Fatalf("expected 100 points, got %d", len(tri.Points))
+	}
+}
+
+func TestQuality(t *testing.T) {
+	rnd := rand.New(rand.NewSource(99))
+	points := grid(100, rnd)
+	tri := validate(t, points)
+	qualities, summary := tri.Quality()
+	if len(qualities) != 162 || summary.Triangles != 162 {
+		t.Fatalf("unexpected triangle count: %d", summary.Triangles)
+	}
+
+	// every triangle of the grid is a right isosceles triangle
+	q := qualities[0]
+	if math.Abs(q.MinAngle-45) > 1e-9 || math.Abs(q.MaxAngle-90) > 1e-9 ||
+		math.Abs(q.AspectRatio-math.Sqrt(3)) > 1e-9 || math.Abs(q.RadiusEdgeRatio-math.Sqrt2/2) > 1e-9 {
+		t.Fatalf("unexpected triangle quality: %+v", q)
+	}
+	if summary.MinArea != 0.5 || summary.MaxArea != 0.5 || summary.StdDevArea != 0 ||
+		summary.MinAngleHistogram[4] != 162 || summary.Slivers != 0 {
+		t.Fatalf("unexpected quality summary: %+v", summary)
+	}
+
+	// a sliver
+	tri = validate(t, []Point{{0, 0}, {1, 0}, {0.5, 0.01}})
+	if _, summary = tri.Quality(); summary.Slivers != 1 {
+		t.Fatal("expected a sliver")
+	}
+}
+
+func TestSmooth(t *testing.T) {
+	for _, method := range []SmoothingMethod{LaplacianSmoothing, AngleBasedSmoothing, ODTSmoothing} {
+		rnd := rand.New(rand.NewSource(99))
+		points := uniform(1000, rnd)
+		tri := validate(t, points)
+		hull := append([]Point(nil), tri.ConvexHull...)
+		_, before := tri.Quality()
+
+		fixed := make([]bool, len(points))
+		fixed[10] = true
+		p := points[10]
+		if err := tri.Smooth(method, 5, fixed); err != nil {
+			t.Fatal(err)
+		}
+		if err := tri.Smooth(method, 1, make([]bool, len(points)+1)); err == nil {
+			t.Fatal("expected an error for too many fixed flags")
+		}
+		if err := tri.Validate(); err != nil {
+			t.Fatal(err)
+		}
+		if points[10] != p {
+			t.Fatal("fixed point moved")
+		}
+		for _, q := range hull {
+			found := false
+			for _, r := range tri.Points {
+				found = found || q == r
+			}
+			if !found {
+				t.Fatal("hull point moved")
+			}
+		}
+		// the slivers along the fixed hull remain, but the interior improves
+		_, after := tri.Quality()
+		good := func(s QualitySummary) int {
+			return s.MinAngleHistogram[4] + s.MinAngleHistogram[5]
+		}
+		if after.Slivers > before.Slivers || good(after) <= good(before) {
+			t.Fatalf("method %d did not improve the quality: %+v", method, after)
+		}
+	}
+}
+
+func TestVoronoiCells(t *testing.T) {
+	rnd := rand.New(rand.NewSource(99))
+	points := uniform(1000, rnd)
+	tri := validate(t, points)
+	min := Point{-0.5, -0.5}
+	max := Point{1.5, 1.5}
+	var total float64
+	for i, cell := range tri.VoronoiCells(min, max) {
+		if len(cell) < 3 || !polygonContains(cell, points[i]) {
+			t.Fatalf("point %d is not inside of its cell", i)
+		}
+		total += polygonArea(cell)
+	}
+	if math.Abs(total-4) > 1e-9 {
+		t.Fatalf("expected cells to cover an area of 4, got %f", total)
+	}
+}
+
+func TestSVG(t *testing.T) {
+	rnd := rand.New(rand.NewSource(99))
+	points := uniform(100, rnd)
+	tri := validate(t, points)
+	options := DefaultSVGOptions()
+	options.Triangles = &SVGStyle{Fill: "#eee"}
+	options.Voronoi = &SVGStyle{Stroke: "blue", StrokeWidth: 0.5}
+	options.Background = `"a" & <b>`
+
+	var buf bytes.Buffer
+	if err := tri.WriteSVG(&buf, options); err != nil {
+		t.Fatal(err)
+	}
+
+	// the output must be well-formed and hold every layer
+	var svg struct {
+		Width   float64 `xml:"width,attr"`
+		Height  float64 `xml:"height,attr"`
+		ViewBox string  `xml:"viewBox,attr"`
+		Paths   []struct {
+			Class string `xml:"class,attr"`
+			D     string `xml:"d,attr"`
+		} `xml:"path"`
+		Circles []struct {
+			X float64 `xml:"cx,attr"`
+			Y float64 `xml:"cy,attr"`
+		} `xml:"g>circle"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &svg); err != nil {
+		t.Fatal(err)
+	}
+	if svg.Width != 800 || svg.ViewBox != fmt.Sprintf("0 0 800.00 %.2f", svg.Height) {
+		t.Fatalf("unexpected size: %f %f %q", svg.Width, svg.Height, svg.ViewBox)
+	}
+	var classes []string
+	for _, path := range svg.Paths {
+		classes = append(classes, path.Class)
+	}
+	if !reflect.DeepEqual(classes, []string{"voronoi", "triangles", "edges", "hull"}) {
+		t.Fatalf("unexpected layers: %v", classes)
+	}
+	if n := strings.Count(svg.Paths[1].D, "M"); n != len(tri.Triangles)/3 {
+		t.Fatalf("expected %d triangles, got %d", len(tri.Triangles)/3, n)
+	}
+	if len(svg.Circles) != len(points) {
+		t.Fatalf("expected %d points, got %d", len(points), len(svg.Circles))
+	}
+	for _, c := range svg.Circles {
+		if c.X < 10-1e-9 || c.X > 790+1e-9 || c.Y < 10-1e-9 || c.Y > svg.Height-10+1e-9 {
+			t.Fatalf("point %v is outside of the image", c)
+		}
+	}
+}
+
+func TestGeoJSON(t *testing.T) {
+	input := `{
+		"type": "FeatureCollection",
+		"features": [
+			{"type": "Feature", "geometry": {"type": "Point", "coordinates": [0, 0]}, "properties": {"name": "a"}},
+			{"type": "Feature", "geometry": {"type": "MultiPoint", "coordinates": [[1, 0], [1, 1, 5]]}, "properties": {"name": "b"}},
+			{"type": "Feature", "geometry": null, "properties": null},
+			{"type": "Feature", "geometry": {"type": "GeometryCollection", "geometries": [
+				{"type": "Point", "coordinates": [0, 1]}
+			]}, "properties": null}
+		]
+	}`
+	points, properties, err := ReadGeoJSON(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(points, []Point{{0, 0}, {1, 0}, {1, 1}, {0, 1}}) {
+		t.Fatalf("unexpected points: %v", points)
+	}
+	if properties[0]["name"] != "a" || properties[2]["name"] != "b" || properties[3] != nil {
+		t.Fatalf("unexpected properties: %v", properties)
+	}
+	if _, _, err := ReadGeoJSON(strings.NewReader(`{"type": "LineString", "coordinates": []}`)); err == nil {
+		t.Fatal("expected an error for unsupported geometries")
+	}
+
+	tri := validate(t, points)
+	type collection struct {
+		Type     string
+		Features []struct {
+			Geometry struct {
+				Type        string
+				Coordinates json.RawMessage
+			}
+			Properties map[string]interface{}
+		}
+	}
+	decode := func(write func(w io.Writer) error) collection {
+		var buf bytes.Buffer
+		if err := write(&buf); err != nil {
+			t.Fatal(err)
+		}
+		var c collection
+		if err := json.Unmarshal(buf.Bytes(), &c); err != nil {
+			t.Fatal(err)
+		}
+		if c.Type != "FeatureCollection" {
+			t.Fatalf("unexpected type: %q", c.Type)
+		}
+		return c
+	}
+	ring := func(raw json.RawMessage) []Point {
+		var rings [][][]float64
+		if err := json.Unmarshal(raw, &rings); err != nil {
+			t.Fatal(err)
+		}
+		var result []Point
+		for _, c := range rings[0] {
+			result = append(result, Point{c[0], c[1]})
+		}
+		if result[0] != result[len(result)-1] {
+			t.Fatal("ring is not closed")
+		}
+		return result[:len(result)-1]
+	}
+
+	c := decode(tri.WriteGeoJSON)
+	if len(c.Features) != 2 {
+		t.Fatalf("expected 2 triangles, got %d", len(c.Features))
+	}
+	for _, f := range c.Features {
+		if f.Geometry.Type != "Polygon" || f.Properties["area"] != 0.5 ||
+			len(f.Properties["points"].([]interface{})) != 3 {
+			t.Fatalf("unexpected feature: %v", f)
+		}
+		if polygonArea(ring(f.Geometry.Coordinates)) != 0.5 {
+			t.Fatal("triangle is not counter-clockwise")
+		}
+	}
+
+	c = decode(tri.WriteHullGeoJSON)
+	if len(c.Features) != 1 || c.Features[0].Properties["area"] != 1.0 ||
+		c.Features[0].Properties["perimeter"] != 4.0 {
+		t.Fatalf("unexpected hull: %v", c.Features)
+	}
+
+	c = decode(func(w io.Writer) error {
+		return tri.WriteVoronoiGeoJSON(w, Point{-1, -1}, Point{2, 2}, properties)
+	})
+	if len(c.Features) != 4 || c.Features[1].Properties["name"] != "b" ||
+		c.Features[1].Properties["index"] != 1.0 {
+		t.Fatalf("unexpected cells: %v", c.Features)
+	}
+	if polygonArea(ring(c.Features[0].Geometry.Coordinates)) != 2.25 {
+		t.Fatal("unexpected cell area")
+	}
+
+	c = decode(func(w io.Writer) error {
+		return tri.WritePointsGeoJSON(w, properties)
+	})
+	if len(c.Features) != 4 || c.Features[0].Properties["name"] != "a" || properties[0]["index"] != nil {
+		t.Fatalf("unexpected points: %v", c.Features)
+	}
+}
+
+func TestMeshFormats(t *testing.T) {
+	rnd := rand.New(rand.NewSource(99))
+	points := uniform(100, rnd)
+	tri := validate(t, points)
+	z := make([]float64, len(points))
+	values := make([]float64, len(points))
+	for i, p := range points {
+		z[i] = p.X * p.Y
+		values[i] = rnd.Float64()
+	}
+
+	check := func(name string, got *Triangulation, gz, gv []float64, err error, wz, wv []float64) {
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if err := got.Validate(); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !reflect.DeepEqual(got.Points, tri.Points) ||
+			!reflect.DeepEqual(got.Triangles, tri.Triangles) ||
+			!reflect.DeepEqual(got.Halfedges, tri.Halfedges) {
+			t.Fatalf("%s: triangulation did not round trip", name)
+		}
+		if !reflect.DeepEqual(gz, wz) || !reflect.DeepEqual(gv, wv) {
+			t.Fatalf("%s: attributes did not round trip", name)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := tri.WriteOBJ(&buf, z, values); err != nil {
+		t.Fatal(err)
+	}
+	got, gz, gv, err := ReadOBJ(&buf)
+	check("obj", got, gz, gv, err, z, values)
+
+	buf.Reset()
+	if err := tri.WriteOBJ(&buf, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	got, _, gv, err = ReadOBJ(&buf)
+	check("obj", got, nil, gv, err, nil, nil)
+
+	for _, binary := range []bool{false, true} {
+		buf.Reset()
+		if err := tri.WritePLY(&buf, z, values, binary); err != nil {
+			t.Fatal(err)
+		}
+		got, gz, gv, err := ReadPLY(&buf)
+		check("ply", got, gz, gv, err, z, values)
+
+		buf.Reset()
+		if err := tri.WritePLY(&buf, nil, values, binary); err != nil {
+			t.Fatal(err)
+		}
+		got, gz, gv, err = ReadPLY(&buf)
+		check("ply", got, gz, gv, err, nil, values)
+	}
+
+	// quads are split into triangles and other elements are skipped
+	ply := "ply\nformat ascii 1.0\ncomment a square\nelement vertex 4\n" +
+		"property float x\nproperty float y\nproperty uchar red\n" +
+		"element face 1\nproperty list uchar uint vertex_index\n" +
+		"element edge 1\nproperty int vertex1\nproperty int vertex2\nend_header\n" +
+		"0 0 255\n1 0 255\n1 1 255\n0 1 255\n4 0 1 2 3\n0 1\n"
+	got, gz, gv, err = ReadPLY(strings.NewReader(ply))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := got.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Triangles) != 6 || gz != nil || gv != nil {
+		t.Fatalf("unexpected mesh: %v", got.Triangles)
+	}
+
+	// the element counts are not trusted
+	for _, format := range []string{"ascii", "binary_little_endian"} {
+		header := "ply\nformat " + format + " 1.0\nelement vertex 999999999999999\n" +
+			"property float x\nproperty float y\nproperty float z\nend_header\n"
+		if _, _, _, err := ReadPLY(strings.NewReader(header)); err != io.ErrUnexpectedEOF {
+			t.Fatalf("%s: expected an unexpected EOF, got %v", format, err)
+		}
+	}
+	if _, _, _, err := ReadOBJ(strings.NewReader("v 0 0 0\nf 1 2 3\n")); err == nil {
+		t.Fatal("expected an error for an out of range index")
+	}
+}
+
+func TestSolid(t *testing.T) {
+	rnd := rand.New(rand.NewSource(99))
+	points := uniform(100, rnd)
+	tri := validate(t, points)
+	z := make([]float64, len(points))
+	var volume float64
+	for i, p := range points {
+		z[i] = 1 + p.X
+	}
+	ts := tri.Triangles
+	for i := 0; i < len(ts); i += 3 {
+		a, b, c := ts[i], ts[i+1], ts[i+2]
+		h := (z[a]+z[b]+z[c])/3 + 1
+		volume += area(points[a], points[b], points[c]) / 2 * h
+	}
+
+	vertices, triangles, err := tri.Solid(z, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// every edge is shared by exactly two triangles in opposite directions
+	for e, h := range computeHalfedges(triangles) {
+		if h < 0 {
+			t.Fatalf("halfedge %d has no twin", e)
+		}
+	}
+
+	// the signed volume is positive when the normals point outwards
+	var v float64
+	for i := 0; i < len(triangles); i += 3 {
+		a := vertices[triangles[i]]
+		b := vertices[triangles[i+1]]
+		c := vertices[triangles[i+2]]
+		v += a.dot(b.cross(c)) / 6
+	}
+	if math.Abs(v-volume) > 1e-9 {
+		t.Fatalf("expected a volume of %f, got %f", volume, v)
+	}
+
+	if _, _, err := tri.Solid(z, 1.5); err == nil {
+		t.Fatal("expected an error for a base above the surface")
+	}
+
+	var buf bytes.Buffer
+	if err := tri.WriteSTL(&buf, z, -1); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	count := int(binary.LittleEndian.Uint32(data[80:]))
+	if count != len(triangles)/3 || len(data) != 84+50*count {
+		t.Fatalf("unexpected STL size: %d triangles, %d bytes", count, len(data))
+	}
+
+	buf.Reset()
+	if err := tri.WriteGLB(&buf, z, -1); err != nil {
+		t.Fatal(err)
+	}
+	data = buf.Bytes()
+	if string(data[:4]) != "glTF" || binary.LittleEndian.Uint32(data[4:]) != 2 ||
+		int(binary.LittleEndian.Uint32(data[8:])) != len(data) {
+		t.Fatal("invalid GLB header")
+	}
+	jsonLength := int(binary.LittleEndian.Uint32(data[12:]))
+	if string(data[16:20]) != "JSON" || jsonLength%4 != 0 {
+		t.Fatal("invalid GLB JSON chunk")
+	}
+	var document struct {
+		Accessors []struct {
+			Count int
+			Min   []float64
+			Max   []float64
+		}
+		Buffers []struct {
+			ByteLength int
+		}
+	}
+	if err := json.Unmarshal(data[20:20+jsonLength], &document); err != nil {
+		t.Fatal(err)
+	}
+	bin := data[20+jsonLength:]
+	if string(bin[4:8]) != "BIN\x00" || int(binary.LittleEndian.Uint32(bin)) != document.Buffers[0].ByteLength ||
+		len(bin) != 8+document.Buffers[0].ByteLength {
+		t.Fatal("invalid GLB binary chunk")
+	}
+	if document.Accessors[0].Count != len(vertices) || document.Accessors[1].Count != len(triangles) ||
+		document.Accessors[0].Min[1] != -1 || document.Accessors[0].Max[1] > 2 {
+		t.Fatalf("unexpected accessors: %+v", document.Accessors)
+	}
+}
+
+func TestTriangleFormat(t *testing.T) {
+	rnd := rand.New(rand.NewSource(99))
+	points := uniform(100, rnd)
+	tri := validate(t, points)
+
+	var node, ele, neigh, edge bytes.Buffer
+	if err := tri.WriteNode(&node); err != nil {
+		t.Fatal(err)
+	}
+	if err := tri.WriteEle(&ele); err != nil {
+		t.Fatal(err)
+	}
+	if err := tri.WriteNeigh(&neigh); err != nil {
+		t.Fatal(err)
+	}
+	if err := tri.WriteEdge(&edge); err != nil {
+		t.Fatal(err)
+	}
+
+	nodes, err := ReadNode(&node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(nodes.Points, points) || nodes.Attributes != nil {
+		t.Fatal("points did not round trip")
+	}
+	got, err := ReadEle(&ele, nodes.Points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Triangles, tri.Triangles) || !reflect.DeepEqual(got.Halfedges, tri.Halfedges) {
+		t.Fatal("triangles did not round trip")
+	}
+	neighbors, err := ReadNeigh(&neigh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(neighbors, tri.Neighbors()) {
+		t.Fatal("neighbors did not round trip")
+	}
+
+	// neighbor j shares the two corners other than corner j
+	ts := tri.Triangles
+	hull := 0
+	for i, n := range neighbors {
+		corners := [3]int{ts[i*3], ts[i*3+2], ts[i*3+1]}
+		for j, k := range n {
+			if k < 0 {
+				hull++
+				continue
+			}
+			shared := 0
+			for _, a := range ts[k*3 : k*3+3] {
+				if a == corners[(j+1)%3] || a == corners[(j+2)%3] {
+					shared++
+				}
+			}
+			if shared != 2 {
+				t.Fatalf("triangle %d is not across from corner %d of triangle %d", k, j, i)
+			}
+		}
+	}
+
+	edges, markers, err := ReadEdge(&edge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	boundary := 0
+	for _, m := range markers {
+		boundary += m
+	}
+	for _, m := range nodes.Markers {
+		boundary -= m
+	}
+	if len(edges) != len(tri.edges()) || boundary != 0 || hull != len(tri.ConvexHull) {
+		t.Fatalf("unexpected edges: %d edges, %d on the boundary", len(edges), boundary)
+	}
+
+	// the dimension, attribute and marker counts may be omitted
+	for _, header := range []string{"3", "3 2", "3 2 0", "3 2 0 0"} {
+		input := header + "\n1 0 0\n2 1 0\n3 0 1\n"
+		nodes, err := ReadNode(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("header %q: %v", header, err)
+		}
+		if len(nodes.Points) != 3 || nodes.Points[2] != (Point{0, 1}) || nodes.Attributes != nil || nodes.Markers != nil {
+			t.Fatalf("header %q: unexpected nodes: %+v", header, nodes)
+		}
+	}
+	nodes, err = ReadNode(strings.NewReader("1 2 1\n1 0 0 5\n"))
+	if err != nil || nodes.Attributes[0][0] != 5 || nodes.Markers != nil {
+		t.Fatalf("unexpected nodes: %+v, %v", nodes, err)
+	}
+	edges, markers, err = ReadEdge(strings.NewReader("1\n0 0 1\n"))
+	if err != nil || edges[0] != [2]int{0, 1} || markers != nil {
+		t.Fatalf("unexpected edges: %v, %v", edges, err)
+	}
+
+	// malformed headers are errors, and counts are not trusted for allocation
+	for _, input := range []string{"-1 3 0\n", "1 3 0\n", "99999999999 3\n1 2 3 4\n"} {
+		if _, err := ReadEle(strings.NewReader(input), points); err == nil {
+			t.Fatalf("ReadEle(%q) did not fail", input)
+		}
+		if _, err := ReadNeigh(strings.NewReader(input)); err == nil {
+			t.Fatalf("ReadNeigh(%q) did not fail", input)
+		}
+	}
+	for _, input := range []string{"-1 2 0 0\n", "1 2 -1 0\n", "1 2 0 -1\n1 0 0\n"} {
+		if _, err := ReadNode(strings.NewReader(input)); err == nil {
+			t.Fatalf("ReadNode(%q) did not fail", input)
+		}
+	}
+	if _, _, err := ReadEdge(strings.NewReader("1 -1\n1 1 2\n")); err == nil {
+		t.Fatal("ReadEdge did not fail on a negative marker count")
+	}
+
+	// a zero-based .poly file in the style of Triangle's examples
+	input := `# a square with a hole
+		4 2 1 1
+		0 0 0 10 1
+		1 1 0 11 1
+		2 1 1 12 1
+		3 0 1 13 1
+		4 1
+		0 0 1 1
+		1 1 2 1
+		2 2 3 1
+		3 3 0 1
+		1
+		0 0.5 0.5
+	`
+	poly, err := ReadPoly(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(poly.Points) != 4 || poly.Attributes[2][0] != 12 || poly.Markers[3] != 1 ||
+		poly.Segments[3] != [2]int{3, 0} || poly.Holes[0] != (Point{0.5, 0.5}) || poly.Regions != nil {
+		t.Fatalf("unexpected poly: %+v", poly)
+	}
+	poly.Regions = []TriangleRegion{{Point{0.1, 0.1}, 2, 0.01}}
+	var buf bytes.Buffer
+	if err := WritePoly(&buf, poly); err != nil {
+		t.Fatal(err)
+	}
+	again, err := ReadPoly(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again, poly) {
+		t.Fatalf("poly did not round trip: %+v", again)
+	}
+}
+
+func TestWKT(t *testing.T) {
+	points, err := ParseWKT("SRID=4326;MULTIPOINT Z ((0 0 1), (1 0 2), 1 1 3, (0 1 4))")
+	if err != nil {
+		t.Fatal(err)
+	}
+	square := []Point{{0, 0}, {1, 0}, {1, 1}, {0, 1}}
+	if !reflect.DeepEqual(points, square) {
+		t.Fatalf("unexpected points: %v", points)
+	}
+	points, err = ParseWKT("polygon((0 0, 1 0, 1 1, 0 1, 0 0), (0.5 0.5, 0.6 0.5, 0.5 0.6, 0.5 0.5))")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 7 || points[4] != (Point{0.5, 0.5}) {
+		t.Fatalf("unexpected points: %v", points)
+	}
+	if points, err := ParseWKT("POINT EMPTY"); err != nil || points != nil {
+		t.Fatal("expected no points")
+	}
+	for _, s := range []string{"LINESTRING (0 0, 1 1)", "MULTIPOINT (0 0, 1)", "POINT (0 0) x", "POLYGON ((0 0, 1 1)"} {
+		if _, err := ParseWKT(s); err == nil {
+			t.Fatalf("expected an error for %q", s)
+		}
+	}
+
+	tri := validate(t, square)
+	if s := tri.HullWKT(); s != "POLYGON ((1 0,1 1,0 1,0 0,1 0))" {
+		t.Fatalf("unexpected hull: %s", s)
+	}
+	if s := tri.WKT(); !strings.HasPrefix(s, "TIN (((") || strings.Count(s, "((") != 2 {
+		t.Fatalf("unexpected TIN: %s", s)
+	}
+	if s := tri.MultiPolygonWKT(); !strings.HasPrefix(s, "MULTIPOLYGON (((") {
+		t.Fatalf("unexpected MULTIPOLYGON: %s", s)
+	}
+	points, err = ParseWKT(tri.HullWKT())
+	if err != nil || !reflect.DeepEqual(points, tri.ConvexHull) {
+		t.Fatalf("hull did not round trip: %v", points)
+	}
+	if (&Triangulation{}).WKT() != "TIN EMPTY" {
+		t.Fatal("expected an empty TIN")
+	}
+}
+
+func TestWKB(t *testing.T) {
+	rnd := rand.New(rand.NewSource(99))
+	points := uniform(100, rnd)
+	tri := validate(t, points)
+	n := len(tri.Triangles) / 3
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		hull, err := ParseWKB(tri.HullWKB(order))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(hull, tri.ConvexHull) {
+			t.Fatal("hull did not round trip")
+		}
+
+		tin := tri.WKB(order)
+		if len(tin) != 9+n*(13+4*16) || order.Uint32(tin[1:]) != 16 || order.Uint32(tin[5:]) != uint32(n) ||
+			order.Uint32(tin[10:]) != 17 {
+			t.Fatal("unexpected TIN")
+		}
+		multi := tri.MultiPolygonWKB(order)
+		if len(multi) != len(tin) || order.Uint32(multi[1:]) != 6 || order.Uint32(multi[10:]) != 3 {
+			t.Fatal("unexpected MULTIPOLYGON")
+		}
+
+		// a PostGIS EWKB MULTIPOINT Z with an SRID, made of points in the
+		// other byte order
+		other := binary.ByteOrder(binary.BigEndian)
+		if order == binary.BigEndian {
+			other = binary.LittleEndian
+		}
+		w := newWKBWriter(order)
+		w.header(0xa0000000 | wkbMultiPoint)
+		w.uint32(4326)
+		w.uint32(uint32(len(points)))
+		for _, p := range points {
+			v := newWKBWriter(other)
+			v.header(1001)
+			for _, x := range []float64{p.X, p.Y, 1} {
+				var buf [8]byte
+				other.PutUint64(buf[:], math.Float64bits(x))
+				v.Write(buf[:])
+			}
+			w.Write(v.Bytes())
+		}
+		got, err := ParseWKB(w.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, points) {
+			t.Fatal("points did not round trip")
+		}
+	}
+	if _, err := ParseWKB([]byte{1, 2, 0, 0, 0}); err == nil {
+		t.Fatal("expected an error for a LINESTRING")
+	}
+}
+
+func TestMarshalBinary(t *testing.T) {
+	rnd := rand.New(rand.NewSource(99))
+	points := uniform(1000, rnd)
+	tri := validate(t, points)
+	data, err := tri.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) >= 16*len(points)+8*len(tri.Triangles) {
+		t.Fatalf("indices were not compressed: %d bytes", len(data))
+	}
+
+	var got Triangulation
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&got, tri) {
+		t.Fatal("triangulation did not round trip")
+	}
+
+	// any change is caught by the checksum
+	for _, i := range []int{0, 4, 10, len(data) / 2, len(data) - 1} {
+		corrupt := append([]byte(nil), data...)
+		corrupt[i] ^= 1
+		if err := got.UnmarshalBinary(corrupt); err == nil {
+			t.Fatalf("expected an error for a change at byte %d", i)
+		}
+	}
+	if err := got.UnmarshalBinary(data[:len(data)-1]); err == nil {
+		t.Fatal("expected an error for truncated data")
+	}
+
+	// an invalid triangulation with a valid checksum is caught by Validate
+	tri.ConvexHull = tri.ConvexHull[1:]
+	data, err = tri.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := got.UnmarshalBinary(data); err == nil {
+		t.Fatal("expected a validation error")
+	}
+
+	// large projected coordinates round trip, and an invalid hull is still
+	// caught at that scale
+	points = uniform(10000, rand.New(rand.NewSource(1)))
+	for i, p := range points {
+		points[i] = Point{5e5 + p.X*1e4, 4e6 + p.Y*1e4}
+	}
+	tri, err = Triangulate(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err = tri.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&got, tri) {
+		t.Fatal("triangulation with large coordinates did not round trip")
+	}
+	tri.ConvexHull = tri.ConvexHull[1:]
+	data, err = tri.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := got.UnmarshalBinary(data); err == nil {
+		t.Fatal("expected a validation error for large coordinates")
+	}
+
+	empty, err := (&Triangulation{}).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := got.UnmarshalBinary(empty); err != nil || len(got.Points) != 0 {
+		t.Fatal("empty triangulation did not round trip")
+	}
+}
+
+func TestVTK(t *testing.T) {
+	rnd := rand.New(rand.NewSource(99))
+	points := uniform(100, rnd)
+	tri := validate(t, points)
+	n := len(points)
+	m := len(tri.Triangles) / 3
+	elevation := VTKField{"elevation", 1, make([]float64, n)}
+	gradient := VTKField{"gradient", 2, make([]float64, 2*n)}
+	qualities, _ := tri.Quality()
+	quality := VTKField{"min_angle", 1, make([]float64, m)}
+	for i, p := range points {
+		elevation.Values[i] = p.X * p.Y
+		gradient.Values[2*i] = p.Y
+		gradient.Values[2*i+1] = p.X
+	}
+	for i, q := range qualities {
+		quality.Values[i] = q.MinAngle
+	}
+	pointFields := []VTKField{elevation, gradient}
+	cellFields := []VTKField{quality}
+
+	var buf bytes.Buffer
+	if err := tri.WriteVTK(&buf, pointFields, cellFields); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	// header, points, cells, cell types, point data, cell data
+	expected := 4 + (1 + n) + (1 + m) + (1 + m) + (1 + 2 + n + 1 + n) + (1 + 2 + m)
+	if len(lines) != expected {
+		t.Fatalf("expected %d lines, got %d", expected, len(lines))
+	}
+	for _, line := range []string{"CELLS " + fmt.Sprint(m, " ", 4*m), "SCALARS elevation double 1",
+		"VECTORS gradient double", "CELL_DATA " + fmt.Sprint(m)} {
+		if !strings.Contains(buf.String(), line+"\n") {
+			t.Fatalf("missing line: %q", line)
+		}
+	}
+
+	buf.Reset()
+	if err := tri.WriteVTU(&buf, pointFields, cellFields); err != nil {
+		t.Fatal(err)
+	}
+	type dataArray struct {
+		Name       string `xml:",attr"`
+		Components int    `xml:"NumberOfComponents,attr"`
+		Text       string `xml:",chardata"`
+	}
+	var vtu struct {
+		Piece struct {
+			Points    int         `xml:"NumberOfPoints,attr"`
+			Cells     int         `xml:"NumberOfCells,attr"`
+			PointData []dataArray `xml:"PointData>DataArray"`
+			CellData  []dataArray `xml:"CellData>DataArray"`
+			Arrays    []dataArray `xml:"Cells>DataArray"`
+		} `xml:"UnstructuredGrid>Piece"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &vtu); err != nil {
+		t.Fatal(err)
+	}
+	p := vtu.Piece
+	if p.Points != n || p.Cells != m || len(p.PointData) != 2 || len(p.CellData) != 1 || len(p.Arrays) != 3 {
+		t.Fatalf("unexpected piece: %d points, %d cells", p.Points, p.Cells)
+	}
+	if p.PointData[1].Name != "gradient" || p.PointData[1].Components != 3 ||
+		len(strings.Fields(p.PointData[1].Text)) != 3*n {
+		t.Fatal("unexpected vector field")
+	}
+	if len(strings.Fields(p.Arrays[0].Text)) != 3*m || len(strings.Fields(p.CellData[0].Text)) != m {
+		t.Fatal("unexpected cells")
+	}
+
+	if err := tri.WriteVTK(&buf, []VTKField{{"bad name", 1, elevation.Values}}, nil); err == nil {
+		t.Fatal("expected an error for an invalid name")
+	}
+	if err := tri.WriteVTU(&buf, nil, []VTKField{elevation}); err == nil {
+		t.Fatal("expected an error for a field of the wrong size")
+	}
+}
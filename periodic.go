@@ -0,0 +1,338 @@
+package delaunay
+
+import (
+	"fmt"
+	"math"
+)
+
+// PeriodicTriangulation is a Delaunay triangulation of the flat torus formed
+// by identifying opposite sides of the rectangular domain [Min, Max]. Points
+// holds the input points wrapped into the domain. Triangles and Halfedges
+// have the same layout as in Triangulation, but every halfedge has a twin,
+// possibly across the domain boundary. Offsets holds one translation per
+// halfedge: corner e of a triangle lies at Points[Triangles[e]] + Offsets[e],
+// which keeps each triangle contiguous even when it wraps around the domain.
+type PeriodicTriangulation struct {
+	Points    []Point
+	Triangles []int
+	Halfedges []int
+	Offsets   []Point
+	Min, Max  Point
+}
+
+type periodicVertex struct {
+	i, ox, oy int
+}
+
+// less orders vertices by index and then offset, which orders the vertices
+// of every translate of a triangle the same way
+func (u periodicVertex) less(v periodicVertex) bool {
+	return u.i < v.i || u.i == v.i && (u.oy < v.oy || u.oy == v.oy && u.ox < v.ox)
+}
+
+// TriangulatePeriodic returns a Delaunay triangulation of the provided points
+// on the flat torus [min, max]. Points outside of the domain are wrapped into
+// it, and points closer to an earlier point than 1e-9 times the size of the
+// domain, also across its boundary, are skipped as duplicates. Cocircular
+// points, like those of a lattice, are split into triangles the same way in
+// every translate. An error is returned if the domain is invalid or there
+// are no points.
+func TriangulatePeriodic(points []Point, min, max Point) (*PeriodicTriangulation, error) {
+	w := max.X - min.X
+	h := max.Y - min.Y
+	if !(w > 0 && h > 0) {
+		return nil, fmt.Errorf("invalid periodic domain")
+	}
+
+	// wrap the points into the domain
+	wrapped := make([]Point, len(points))
+	for i, p := range points {
+		x := math.Mod(p.X-min.X, w)
+		y := math.Mod(p.Y-min.Y, h)
+		if x < 0 {
+			x += w
+		}
+		if y < 0 {
+			y += h
+		}
+
+		// tiny negative offsets wrap around to exactly max, which belongs
+		// to the other side of the domain
+		q := Point{min.X + x, min.Y + y}
+		if q.X >= max.X {
+			q.X = min.X
+		}
+		if q.Y >= max.Y {
+			q.Y = min.Y
+		}
+		wrapped[i] = q
+	}
+
+	// skip nearly-duplicate points; points within the tolerance of each
+	// other fall into the same or neighboring cells of a grid whose cells
+	// are as large as the tolerance, which wraps around like the domain
+	const tolerance = 1e-9
+	n := int64(1 / tolerance)
+	type cell struct {
+		x, y int64
+	}
+	grid := make(map[cell][]int)
+	var ids []int
+	for i, p := range wrapped {
+		cx := int64((p.X-min.X)/w*float64(n)) % n
+		cy := int64((p.Y-min.Y)/h*float64(n)) % n
+		duplicate := false
+		for dy := int64(-1); dy <= 1 && !duplicate; dy++ {
+			for dx := int64(-1); dx <= 1 && !duplicate; dx++ {
+				for _, j := range grid[cell{(cx + dx + n) % n, (cy + dy + n) % n}] {
+					x := math.Abs(p.X - wrapped[j].X)
+					y := math.Abs(p.Y - wrapped[j].Y)
+					if math.Min(x, w-x) <= tolerance*w && math.Min(y, h-y) <= tolerance*h {
+						duplicate = true
+						break
+					}
+				}
+			}
+		}
+		if !duplicate {
+			grid[cell{cx, cy}] = append(grid[cell{cx, cy}], i)
+			ids = append(ids, i)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("No periodic Delaunay triangulation exists for this input.")
+	}
+
+	// triangulate the points along with copies of those within a margin of
+	// the domain, growing the margin until the triangles are all known to be
+	// periodic Delaunay triangles; no circumcircle is wider than the
+	// diagonal of the domain, so a margin of w+h always suffices
+	margin := math.Min(2*math.Sqrt(w*h/float64(len(ids))), w+h)
+	for {
+		t, ok := triangulatePeriodic(wrapped, ids, min, max, margin)
+		if ok {
+			return t, nil
+		}
+		if margin == w+h {
+			return nil, fmt.Errorf("No periodic Delaunay triangulation exists for this input.")
+		}
+		margin = math.Min(2*margin, w+h)
+	}
+}
+
+func triangulatePeriodic(points []Point, ids []int, min, max Point, margin float64) (*PeriodicTriangulation, bool) {
+	w := max.X - min.X
+	h := max.Y - min.Y
+	x0, y0 := min.X-margin, min.Y-margin
+	x1, y1 := max.X+margin, max.Y+margin
+	kx := int(math.Ceil(margin / w))
+	ky := int(math.Ceil(margin / h))
+
+	// the copies are triangulated relative to the domain and scaled to its
+	// size, because Triangulate skips duplicates by an absolute distance
+	s := math.Max(w, h)
+	normalize := func(p Point) Point {
+		return Point{(p.X - min.X) / s, (p.Y - min.Y) / s}
+	}
+	p0 := normalize(Point{x0, y0})
+	p1 := normalize(Point{x1, y1})
+
+	var extended []Point
+	var vertices []periodicVertex
+	for _, i := range ids {
+		for oy := -ky; oy <= ky; oy++ {
+			for ox := -kx; ox <= kx; ox++ {
+				p := Point{points[i].X + float64(ox)*w, points[i].Y + float64(oy)*h}
+				if p.X < x0 || p.X > x1 || p.Y < y0 || p.Y > y1 {
+					continue
+				}
+				extended = append(extended, normalize(p))
+				vertices = append(vertices, periodicVertex{i, ox, oy})
+			}
+		}
+	}
+	tri, err := Triangulate(extended)
+	if err != nil {
+		return nil, false
+	}
+
+	// Triangulate splits cocircular polygons, like the squares of a lattice,
+	// differently in each translate, so first merge the triangles into cells
+	// of cocircular points, which are the same in every translate
+	ts := tri.Triangles
+	hs := tri.Halfedges
+	parent := make([]int, len(ts)/3)
+	for i := range parent {
+		parent[i] = i
+	}
+	find := func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+	for e, h := range hs {
+		if e > h && h >= 0 {
+			a := extended[ts[e]]
+			b := extended[ts[nextHalfedge(e)]]
+			c := extended[ts[prevHalfedge(e)]]
+			if periodicIncircle(a, b, c, extended[ts[prevHalfedge(h)]]) == 0 {
+				parent[find(e/3)] = find(h / 3)
+			}
+		}
+	}
+	boundary := func(e int) bool {
+		return hs[e] < 0 || find(e/3) != find(hs[e]/3)
+	}
+
+	// keep one translate of each cell: the one whose lowest vertex lies in
+	// the domain itself, and split it into a fan around that vertex
+	var triangles []int
+	var offsets []periodicVertex
+	var ring []int
+	visited := make([]bool, len(hs))
+	for start := range hs {
+		if visited[start] || !boundary(start) {
+			continue
+		}
+		ring = ring[:0]
+		for e := start; !visited[e]; {
+			visited[e] = true
+			ring = append(ring, ts[e])
+
+			// rotate around the end point to the next boundary halfedge
+			e = nextHalfedge(e)
+			for !boundary(e) {
+				e = nextHalfedge(hs[e])
+			}
+		}
+		m := 0
+		for k, j := range ring {
+			if vertices[j].less(vertices[ring[m]]) {
+				m = k
+			}
+		}
+		if vertices[ring[m]].ox != 0 || vertices[ring[m]].oy != 0 {
+			continue
+		}
+
+		// the circumcircle must not reach past the copied points
+		a, b, c := extended[ring[0]], extended[ring[1]], extended[ring[2]]
+		center := circumcenter(a, b, c)
+		r := center.distance(a)
+		if center.X-r < p0.X || center.X+r > p1.X || center.Y-r < p0.Y || center.Y+r > p1.Y {
+			return nil, false
+		}
+
+		for k := 1; k+1 < len(ring); k++ {
+			for _, j := range [3]int{ring[m], ring[(m+k)%len(ring)], ring[(m+k+1)%len(ring)]} {
+				triangles = append(triangles, vertices[j].i)
+				offsets = append(offsets, vertices[j])
+			}
+		}
+	}
+
+	// a closed triangulation of the torus has twice as many triangles as
+	// points, and every halfedge has a twin
+	if len(triangles)/3 != 2*len(ids) {
+		return nil, false
+	}
+	halfedges := make([]int, len(triangles))
+	edges := make(map[[4]int]int, len(triangles))
+	for e := range triangles {
+		a := offsets[e]
+		b := offsets[nextHalfedge(e)]
+		key := [4]int{a.i, b.i, b.ox - a.ox, b.oy - a.oy}
+		edges[key] = e
+	}
+	for e := range triangles {
+		a := offsets[e]
+		b := offsets[nextHalfedge(e)]
+		twin, ok := edges[[4]int{b.i, a.i, a.ox - b.ox, a.oy - b.oy}]
+		if !ok {
+			return nil, false
+		}
+		halfedges[e] = twin
+	}
+
+	result := &PeriodicTriangulation{
+		Points:    points,
+		Triangles: triangles,
+		Halfedges: halfedges,
+		Offsets:   make([]Point, len(offsets)),
+		Min:       min,
+		Max:       max,
+	}
+	for e, u := range offsets {
+		result.Offsets[e] = Point{float64(u.ox) * w, float64(u.oy) * h}
+	}
+	return result, true
+}
+
+// periodicIncircle returns 1 if p lies inside the circumcircle of a, b and c,
+// -1 if it lies outside and 0 if it lies on it within a tolerance relative to
+// the radius, so that cocircular points, which round differently in each
+// translate, are treated alike in all of them
+func periodicIncircle(a, b, c, p Point) int {
+	center := circumcenter(a, b, c)
+	r := center.distance(a)
+	d := center.distance(p) - r
+	if math.Abs(d) <= 1e-9*r {
+		return 0
+	}
+	if d < 0 {
+		return 1
+	}
+	return -1
+}
+
+// Position returns the position of corner e of its triangle, which may lie
+// outside of the domain for triangles that wrap around it.
+func (t *PeriodicTriangulation) Position(e int) Point {
+	return t.Points[t.Triangles[e]].add(t.Offsets[e])
+}
+
+// Validate performs several sanity checks on the PeriodicTriangulation to
+// check for potential errors. Returns nil if no issues were found.
+func (t *PeriodicTriangulation) Validate() error {
+	// verify halfedges and that twins agree on the edge vector
+	for e, h := range t.Halfedges {
+		if h < 0 || t.Halfedges[h] != e {
+			return fmt.Errorf("invalid halfedge connection")
+		}
+		d1 := t.Position(nextHalfedge(e)).sub(t.Position(e))
+		d2 := t.Position(h).sub(t.Position(nextHalfedge(h)))
+		if d1.squaredDistance(d2) > 1e-9 {
+			return fmt.Errorf("halfedge offsets disagree")
+		}
+	}
+
+	// verify euler characteristic of the torus
+	used := make(map[int]bool)
+	for _, i := range t.Triangles {
+		used[i] = true
+	}
+	if len(t.Triangles)/3 != 2*len(used) {
+		return fmt.Errorf("triangulation is not closed: %d triangles, %d points",
+			len(t.Triangles)/3, len(used))
+	}
+
+	// verify orientation and the empty circumcircle property for each edge
+	for e, h := range t.Halfedges {
+		i := e - e%3
+		a := t.Position(i)
+		b := t.Position(i + 1)
+		c := t.Position(i + 2)
+		if orient2d(a, b, c) <= 0 {
+			return fmt.Errorf("triangle %d is inverted", i/3)
+		}
+		shift := t.Position(e).sub(t.Position(nextHalfedge(h)))
+		p := t.Position(prevHalfedge(h)).add(shift)
+		if periodicIncircle(a, b, c, p) > 0 {
+			return fmt.Errorf("triangle %d is not delaunay", i/3)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,312 @@
+package delaunay
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// WritePLY writes the triangulation as a PLY mesh, in the ASCII format or in
+// the binary little endian format. z and values, which may be nil, hold the
+// elevation and a scalar value of each point and are written as the "z" and
+// "value" vertex properties. Faces are written counter-clockwise so that
+// their normals point up.
+func (t *Triangulation) WritePLY(w io.Writer, z, values []float64, binaryFormat bool) error {
+	if z != nil && len(z) != len(t.Points) {
+		return fmt.Errorf("expected %d elevations, got %d", len(t.Points), len(z))
+	}
+	if values != nil && len(values) != len(t.Points) {
+		return fmt.Errorf("expected %d values, got %d", len(t.Points), len(values))
+	}
+	ts := t.Triangles
+
+	bw := bufio.NewWriter(w)
+	format := "ascii"
+	if binaryFormat {
+		format = "binary_little_endian"
+	}
+	fmt.Fprintln(bw, "ply")
+	fmt.Fprintf(bw, "format %s 1.0\n", format)
+	fmt.Fprintf(bw, "element vertex %d\n", len(t.Points))
+	fmt.Fprintln(bw, "property double x")
+	fmt.Fprintln(bw, "property double y")
+	if z != nil {
+		fmt.Fprintln(bw, "property double z")
+	}
+	if values != nil {
+		fmt.Fprintln(bw, "property double value")
+	}
+	fmt.Fprintf(bw, "element face %d\n", len(ts)/3)
+	fmt.Fprintln(bw, "property list uchar int vertex_indices")
+	fmt.Fprintln(bw, "end_header")
+
+	for i, p := range t.Points {
+		row := []float64{p.X, p.Y}
+		if z != nil {
+			row = append(row, z[i])
+		}
+		if values != nil {
+			row = append(row, values[i])
+		}
+		if binaryFormat {
+			var buf [8]byte
+			for _, x := range row {
+				binary.LittleEndian.PutUint64(buf[:], math.Float64bits(x))
+				bw.Write(buf[:])
+			}
+			continue
+		}
+		for j, x := range row {
+			if j > 0 {
+				bw.WriteByte(' ')
+			}
+			bw.WriteString(formatFloat(x))
+		}
+		bw.WriteByte('\n')
+	}
+
+	for i := 0; i < len(ts)/3; i++ {
+		face := t.corners(i)
+		if binaryFormat {
+			var buf [13]byte
+			buf[0] = 3
+			for j, k := range face {
+				binary.LittleEndian.PutUint32(buf[1+j*4:], uint32(k))
+			}
+			bw.Write(buf[:])
+			continue
+		}
+		fmt.Fprintf(bw, "3 %d %d %d\n", face[0], face[1], face[2])
+	}
+	return bw.Flush()
+}
+
+type plyProperty struct {
+	name      string
+	typ       string
+	countType string // empty unless this is a list property
+}
+
+type plyElement struct {
+	name       string
+	count      int
+	properties []plyProperty
+}
+
+// ReadPLY reads a PLY mesh in the ASCII or either binary format, returning
+// the triangulation along with the "z" and "value" vertex properties, which
+// are nil if the file does not have them. Polygon faces are split into
+// triangles, and Halfedges and ConvexHull are recomputed. The mesh is
+// projected onto the xy plane, so it should be a height field for the
+// result to be a valid triangulation.
+func ReadPLY(r io.Reader) (*Triangulation, []float64, []float64, error) {
+	br := bufio.NewReader(r)
+
+	// parse the header
+	var format string
+	var elements []*plyElement
+	for first, done := true, false; !done; first = false {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("invalid PLY header: %v", err)
+		}
+		fields := strings.Fields(line)
+		if first {
+			if len(fields) != 1 || fields[0] != "ply" {
+				return nil, nil, nil, fmt.Errorf("not a PLY file")
+			}
+			continue
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "format":
+			if len(fields) < 2 {
+				return nil, nil, nil, fmt.Errorf("invalid PLY format")
+			}
+			format = fields[1]
+		case "element":
+			if len(fields) != 3 {
+				return nil, nil, nil, fmt.Errorf("invalid PLY element")
+			}
+			count, err := strconv.Atoi(fields[2])
+			if err != nil || count < 0 {
+				return nil, nil, nil, fmt.Errorf("invalid PLY element count: %q", fields[2])
+			}
+			elements = append(elements, &plyElement{name: fields[1], count: count})
+		case "property":
+			if len(elements) == 0 {
+				return nil, nil, nil, fmt.Errorf("PLY property outside of an element")
+			}
+			var p plyProperty
+			if len(fields) == 5 && fields[1] == "list" {
+				p = plyProperty{fields[4], fields[3], fields[2]}
+			} else if len(fields) == 3 {
+				p = plyProperty{fields[2], fields[1], ""}
+			} else {
+				return nil, nil, nil, fmt.Errorf("invalid PLY property")
+			}
+			for _, typ := range []string{p.typ, p.countType} {
+				if typ != "" && plySize(typ) == 0 {
+					return nil, nil, nil, fmt.Errorf("unsupported PLY type: %q", typ)
+				}
+			}
+			e := elements[len(elements)-1]
+			e.properties = append(e.properties, p)
+		case "end_header":
+			done = true
+		}
+	}
+
+	var read func(typ string) (float64, error)
+	switch format {
+	case "ascii":
+		read = plyASCIIReader(br)
+	case "binary_little_endian":
+		read = plyBinaryReader(br, binary.LittleEndian)
+	case "binary_big_endian":
+		read = plyBinaryReader(br, binary.BigEndian)
+	default:
+		return nil, nil, nil, fmt.Errorf("unsupported PLY format: %q", format)
+	}
+
+	var points []Point
+	var z, values []float64
+	var triangles, face []int
+	for _, e := range elements {
+		if e.name == "vertex" {
+			// the count comes from the header, so it only bounds how much
+			// is preallocated; a shorter body fails when it runs out
+			n := e.count
+			if n > 1<<16 {
+				n = 1 << 16
+			}
+			points = make([]Point, 0, n)
+			for _, p := range e.properties {
+				switch p.name {
+				case "z":
+					z = make([]float64, 0, n)
+				case "value":
+					values = make([]float64, 0, n)
+				}
+			}
+		}
+		for k := 0; k < e.count; k++ {
+			var p Point
+			for _, prop := range e.properties {
+				if prop.countType != "" {
+					n, err := read(prop.countType)
+					if err != nil {
+						return nil, nil, nil, err
+					}
+					face = face[:0]
+					for j := 0; j < int(n); j++ {
+						x, err := read(prop.typ)
+						if err != nil {
+							return nil, nil, nil, err
+						}
+						face = append(face, int(x))
+					}
+					if e.name == "face" && (prop.name == "vertex_indices" || prop.name == "vertex_index") {
+						if len(face) < 3 {
+							return nil, nil, nil, fmt.Errorf("invalid PLY face")
+						}
+						triangles = fan(triangles, face)
+					}
+					continue
+				}
+				x, err := read(prop.typ)
+				if err != nil {
+					return nil, nil, nil, err
+				}
+				if e.name != "vertex" {
+					continue
+				}
+				switch prop.name {
+				case "x":
+					p.X = x
+				case "y":
+					p.Y = x
+				case "z":
+					z = append(z, x)
+				case "value":
+					values = append(values, x)
+				}
+			}
+			if e.name == "vertex" {
+				points = append(points, p)
+			}
+		}
+	}
+
+	t, err := newMeshTriangulation(points, triangles)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return t, z, values, nil
+}
+
+// plySize returns the size in bytes of a PLY scalar type, or 0 if the type
+// is unknown
+func plySize(typ string) int {
+	switch typ {
+	case "char", "uchar", "int8", "uint8":
+		return 1
+	case "short", "ushort", "int16", "uint16":
+		return 2
+	case "int", "uint", "int32", "uint32", "float", "float32":
+		return 4
+	case "double", "float64":
+		return 8
+	}
+	return 0
+}
+
+func plyASCIIReader(r *bufio.Reader) func(typ string) (float64, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Split(bufio.ScanWords)
+	return func(typ string) (float64, error) {
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return 0, err
+			}
+			return 0, io.ErrUnexpectedEOF
+		}
+		return strconv.ParseFloat(scanner.Text(), 64)
+	}
+}
+
+func plyBinaryReader(r *bufio.Reader, order binary.ByteOrder) func(typ string) (float64, error) {
+	var buf [8]byte
+	return func(typ string) (float64, error) {
+		b := buf[:plySize(typ)]
+		if _, err := io.ReadFull(r, b); err == io.EOF {
+			return 0, io.ErrUnexpectedEOF
+		} else if err != nil {
+			return 0, err
+		}
+		switch typ {
+		case "char", "int8":
+			return float64(int8(b[0])), nil
+		case "uchar", "uint8":
+			return float64(b[0]), nil
+		case "short", "int16":
+			return float64(int16(order.Uint16(b))), nil
+		case "ushort", "uint16":
+			return float64(order.Uint16(b)), nil
+		case "int", "int32":
+			return float64(int32(order.Uint32(b))), nil
+		case "uint", "uint32":
+			return float64(order.Uint32(b)), nil
+		case "float", "float32":
+			return float64(math.Float32frombits(order.Uint32(b))), nil
+		default:
+			return math.Float64frombits(order.Uint64(b)), nil
+		}
+	}
+}
@@ -19,3 +19,23 @@ func (a Point) distance(b Point) float64 {
 func (a Point) sub(b Point) Point {
 	return Point{a.X - b.X, a.Y - b.Y}
 }
+
+func (a Point) add(b Point) Point {
+	return Point{a.X + b.X, a.Y + b.Y}
+}
+
+func (a Point) scale(s float64) Point {
+	return Point{a.X * s, a.Y * s}
+}
+
+func (a Point) length() float64 {
+	return math.Hypot(a.X, a.Y)
+}
+
+func (a Point) dot(b Point) float64 {
+	return a.X*b.X + a.Y*b.Y
+}
+
+func (a Point) finite() bool {
+	return !math.IsNaN(a.X) && !math.IsNaN(a.Y) && !math.IsInf(a.X, 0) && !math.IsInf(a.Y, 0)
+}
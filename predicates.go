@@ -0,0 +1,213 @@
+package delaunay
+
+import (
+	"math"
+	"math/big"
+)
+
+// epsilon is half of the machine epsilon, used for the error bounds of the
+// floating point filters below
+var epsilon = math.Ldexp(1, -53)
+
+var (
+	o2dErrBound = 4 * epsilon
+	pwrErrBound = 32 * epsilon
+	o3dErrBound = 16 * epsilon
+	ispErrBound = 48 * epsilon
+)
+
+// orient2d returns a positive value if a, b and c have the same winding as
+// the triangles produced by Triangulate, a negative value if they have the
+// opposite winding and zero if they are collinear. The sign of the result is
+// exact.
+func orient2d(a, b, c Point) float64 {
+	l := (b.X - a.X) * (c.Y - a.Y)
+	r := (b.Y - a.Y) * (c.X - a.X)
+	det := l - r
+	if math.Abs(det) > o2dErrBound*(math.Abs(l)+math.Abs(r)) {
+		return -det
+	}
+	return -orient2dExact(a, b, c)
+}
+
+// powerTest returns a positive value if the weighted point (p, wp) is in
+// conflict with the triangle of weighted points (a, wa), (b, wb), (c, wc),
+// that is, if p has a smaller power distance to the triangle's orthogonal
+// circle than zero. With all weights equal this is the in-circle test. The
+// triangle must be positively oriented according to orient2d. The sign of the
+// result is exact.
+func powerTest(a, b, c, p Point, wa, wb, wc, wp float64) float64 {
+	adx, ady := a.X-p.X, a.Y-p.Y
+	bdx, bdy := b.X-p.X, b.Y-p.Y
+	cdx, cdy := c.X-p.X, c.Y-p.Y
+
+	alift := adx*adx + ady*ady - wa + wp
+	blift := bdx*bdx + bdy*bdy - wb + wp
+	clift := cdx*cdx + cdy*cdy - wc + wp
+
+	det := alift*(bdx*cdy-bdy*cdx) - blift*(adx*cdy-ady*cdx) + clift*(adx*bdy-ady*bdx)
+
+	w := math.Abs(wp)
+	permanent := (adx*adx+ady*ady+math.Abs(wa)+w)*(math.Abs(bdx*cdy)+math.Abs(bdy*cdx)) +
+		(bdx*bdx+bdy*bdy+math.Abs(wb)+w)*(math.Abs(adx*cdy)+math.Abs(ady*cdx)) +
+		(cdx*cdx+cdy*cdy+math.Abs(wc)+w)*(math.Abs(adx*bdy)+math.Abs(ady*bdx))
+	if math.Abs(det) > pwrErrBound*permanent {
+		return -det
+	}
+	return -powerTestExact(a, b, c, p, wa, wb, wc, wp)
+}
+
+// orient3d returns a positive value if d lies on the side of the plane
+// through a, b and c that makes (a, b, c, d) a right-handed tetrahedron, a
+// negative value if it lies on the other side and zero if the four points are
+// coplanar. The sign of the result is exact.
+func orient3d(a, b, c, d Vector) float64 {
+	adx, ady, adz := a.X-d.X, a.Y-d.Y, a.Z-d.Z
+	bdx, bdy, bdz := b.X-d.X, b.Y-d.Y, b.Z-d.Z
+	cdx, cdy, cdz := c.X-d.X, c.Y-d.Y, c.Z-d.Z
+
+	det := adx*(bdy*cdz-bdz*cdy) + bdx*(cdy*adz-cdz*ady) + cdx*(ady*bdz-adz*bdy)
+
+	permanent := math.Abs(adx)*(math.Abs(bdy*cdz)+math.Abs(bdz*cdy)) +
+		math.Abs(bdx)*(math.Abs(cdy*adz)+math.Abs(cdz*ady)) +
+		math.Abs(cdx)*(math.Abs(ady*bdz)+math.Abs(adz*bdy))
+	if math.Abs(det) > o3dErrBound*permanent {
+		return -det
+	}
+	return -orient3dExact(a, b, c, d)
+}
+
+// insphere returns a positive value if e lies inside the sphere passing
+// through a, b, c and d, a negative value if it lies outside and zero if the
+// five points are cospherical. The tetrahedron (a, b, c, d) must be positively
+// oriented according to orient3d. The sign of the result is exact.
+func insphere(a, b, c, d, e Vector) float64 {
+	aex, aey, aez := a.X-e.X, a.Y-e.Y, a.Z-e.Z
+	bex, bey, bez := b.X-e.X, b.Y-e.Y, b.Z-e.Z
+	cex, cey, cez := c.X-e.X, c.Y-e.Y, c.Z-e.Z
+	dex, dey, dez := d.X-e.X, d.Y-e.Y, d.Z-e.Z
+
+	alift := aex*aex + aey*aey + aez*aez
+	blift := bex*bex + bey*bey + bez*bez
+	clift := cex*cex + cey*cey + cez*cez
+	dlift := dex*dex + dey*dey + dez*dez
+
+	det3 := func(ax, ay, az, bx, by, bz, cx, cy, cz float64) (float64, float64) {
+		d := ax*(by*cz-bz*cy) + bx*(cy*az-cz*ay) + cx*(ay*bz-az*by)
+		p := math.Abs(ax)*(math.Abs(by*cz)+math.Abs(bz*cy)) +
+			math.Abs(bx)*(math.Abs(cy*az)+math.Abs(cz*ay)) +
+			math.Abs(cx)*(math.Abs(ay*bz)+math.Abs(az*by))
+		return d, p
+	}
+
+	bcd, bcdp := det3(bex, bey, bez, cex, cey, cez, dex, dey, dez)
+	acd, acdp := det3(aex, aey, aez, cex, cey, cez, dex, dey, dez)
+	abd, abdp := det3(aex, aey, aez, bex, bey, bez, dex, dey, dez)
+	abc, abcp := det3(aex, aey, aez, bex, bey, bez, cex, cey, cez)
+
+	det := (alift*bcd - blift*acd) + (clift*abd - dlift*abc)
+	permanent := dlift*abcp + clift*abdp + blift*acdp + alift*bcdp
+	if math.Abs(det) > ispErrBound*permanent {
+		return det
+	}
+	return insphereExact(a, b, c, d, e)
+}
+
+func orient2dExact(a, b, c Point) float64 {
+	ra := newRatPoint(a)
+	ba := newRatPoint(b).sub(ra)
+	ca := newRatPoint(c).sub(ra)
+	det := new(big.Rat).Mul(ba[0], ca[1])
+	det.Sub(det, new(big.Rat).Mul(ba[1], ca[0]))
+	return float64(det.Sign())
+}
+
+func powerTestExact(a, b, c, p Point, wa, wb, wc, wp float64) float64 {
+	rp := newRatPoint(p)
+	ap := newRatPoint(a).sub(rp)
+	bp := newRatPoint(b).sub(rp)
+	cp := newRatPoint(c).sub(rp)
+
+	w := new(big.Rat).SetFloat64(wp)
+	lift := func(v ratVector, wv float64) *big.Rat {
+		l := v.lift()
+		l.Sub(l, new(big.Rat).SetFloat64(wv))
+		return l.Add(l, w)
+	}
+	ap[2] = lift(ap, wa)
+	bp[2] = lift(bp, wb)
+	cp[2] = lift(cp, wc)
+	return float64(ratDet3(ap, bp, cp).Sign())
+}
+
+func orient3dExact(a, b, c, d Vector) float64 {
+	ad := newRatVector(a).sub(newRatVector(d))
+	bd := newRatVector(b).sub(newRatVector(d))
+	cd := newRatVector(c).sub(newRatVector(d))
+	det := ratDet3(ad, bd, cd)
+	return float64(det.Sign())
+}
+
+func insphereExact(a, b, c, d, e Vector) float64 {
+	re := newRatVector(e)
+	ae := newRatVector(a).sub(re)
+	be := newRatVector(b).sub(re)
+	ce := newRatVector(c).sub(re)
+	de := newRatVector(d).sub(re)
+
+	det := new(big.Rat)
+	tmp := new(big.Rat)
+	det.Add(det, tmp.Mul(ae.lift(), ratDet3(be, ce, de)))
+	det.Sub(det, tmp.Mul(be.lift(), ratDet3(ae, ce, de)))
+	det.Add(det, tmp.Mul(ce.lift(), ratDet3(ae, be, de)))
+	det.Sub(det, tmp.Mul(de.lift(), ratDet3(ae, be, ce)))
+	return float64(det.Sign())
+}
+
+type ratVector [3]*big.Rat
+
+func newRatVector(v Vector) ratVector {
+	return ratVector{
+		new(big.Rat).SetFloat64(v.X),
+		new(big.Rat).SetFloat64(v.Y),
+		new(big.Rat).SetFloat64(v.Z),
+	}
+}
+
+// newRatPoint returns a ratVector with a zero third component
+func newRatPoint(p Point) ratVector {
+	return ratVector{
+		new(big.Rat).SetFloat64(p.X),
+		new(big.Rat).SetFloat64(p.Y),
+		new(big.Rat),
+	}
+}
+
+func (a ratVector) sub(b ratVector) ratVector {
+	var r ratVector
+	for i := range r {
+		r[i] = new(big.Rat).Sub(a[i], b[i])
+	}
+	return r
+}
+
+func (a ratVector) lift() *big.Rat {
+	r := new(big.Rat)
+	tmp := new(big.Rat)
+	for i := range a {
+		r.Add(r, tmp.Mul(a[i], a[i]))
+	}
+	return r
+}
+
+func ratDet3(a, b, c ratVector) *big.Rat {
+	minor := func(p, q, r, s *big.Rat) *big.Rat {
+		// p*q - r*s
+		x := new(big.Rat).Mul(p, q)
+		return x.Sub(x, new(big.Rat).Mul(r, s))
+	}
+	det := new(big.Rat).Mul(a[0], minor(b[1], c[2], b[2], c[1]))
+	det.Add(det, new(big.Rat).Mul(b[0], minor(c[1], a[2], c[2], a[1])))
+	det.Add(det, new(big.Rat).Mul(c[0], minor(a[1], b[2], a[2], b[1])))
+	return det
+}
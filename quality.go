@@ -0,0 +1,120 @@
+package delaunay
+
+import "math"
+
+// SliverAngle is the minimum angle, in degrees, below which a triangle is
+// counted as a sliver by Quality.
+const SliverAngle = 5
+
+// TriangleQuality holds shape metrics of a single triangle. Angles are in
+// degrees.
+type TriangleQuality struct {
+	Area     float64
+	MinAngle float64
+	MaxAngle float64
+
+	// AspectRatio is the longest edge divided by the shortest altitude,
+	// scaled so that an equilateral triangle has an aspect ratio of 1.
+	AspectRatio float64
+
+	// RadiusEdgeRatio is the circumradius divided by the shortest edge,
+	// which is 1/sqrt(3) for an equilateral triangle.
+	RadiusEdgeRatio float64
+}
+
+// QualitySummary aggregates the TriangleQuality of every triangle.
+type QualitySummary struct {
+	Triangles int
+
+	MinAngle float64
+	MaxAngle float64
+
+	// MinAngleHistogram counts the triangles by their minimum angle, in
+	// bins of 10 degrees from [0, 10) to [50, 60].
+	MinAngleHistogram [6]int
+
+	MeanAspectRatio     float64
+	MaxAspectRatio      float64
+	MeanRadiusEdgeRatio float64
+	MaxRadiusEdgeRatio  float64
+
+	MinArea    float64
+	MaxArea    float64
+	MeanArea   float64
+	StdDevArea float64
+
+	// Slivers counts the triangles with a minimum angle below SliverAngle.
+	Slivers int
+}
+
+func triangleQuality(a, b, c Point) TriangleQuality {
+	la := b.distance(c)
+	lb := c.distance(a)
+	lc := a.distance(b)
+	angle := func(opposite, s1, s2 float64) float64 {
+		cos := (s1*s1 + s2*s2 - opposite*opposite) / (2 * s1 * s2)
+		return math.Acos(math.Max(-1, math.Min(1, cos))) * 180 / math.Pi
+	}
+	angles := [3]float64{angle(la, lb, lc), angle(lb, lc, la), angle(lc, la, lb)}
+	shortest := math.Min(la, math.Min(lb, lc))
+	longest := math.Max(la, math.Max(lb, lc))
+	area := math.Abs(area(a, b, c)) / 2
+
+	var q TriangleQuality
+	q.Area = area
+	q.MinAngle = math.Min(angles[0], math.Min(angles[1], angles[2]))
+	q.MaxAngle = math.Max(angles[0], math.Max(angles[1], angles[2]))
+	if area > 0 {
+		altitude := 2 * area / longest
+		q.AspectRatio = longest / altitude * math.Sqrt(3) / 2
+		q.RadiusEdgeRatio = la * lb * lc / (4 * area) / shortest
+	} else {
+		q.AspectRatio = infinity
+		q.RadiusEdgeRatio = infinity
+	}
+	return q
+}
+
+// Quality computes shape metrics for every triangle of the triangulation,
+// indexed like the triangles in Triangles, along with a summary of them. It
+// complements Validate, which checks correctness rather than shape.
+func (t *Triangulation) Quality() ([]TriangleQuality, QualitySummary) {
+	points := t.Points
+	ts := t.Triangles
+	qualities := make([]TriangleQuality, len(ts)/3)
+	var s QualitySummary
+	s.Triangles = len(qualities)
+	if s.Triangles == 0 {
+		return qualities, s
+	}
+	s.MinAngle = infinity
+	s.MinArea = infinity
+	for i := range qualities {
+		q := triangleQuality(points[ts[i*3]], points[ts[i*3+1]], points[ts[i*3+2]])
+		qualities[i] = q
+
+		s.MinAngle = math.Min(s.MinAngle, q.MinAngle)
+		s.MaxAngle = math.Max(s.MaxAngle, q.MaxAngle)
+		s.MinAngleHistogram[int(math.Min(q.MinAngle/10, 5))]++
+		s.MeanAspectRatio += q.AspectRatio
+		s.MaxAspectRatio = math.Max(s.MaxAspectRatio, q.AspectRatio)
+		s.MeanRadiusEdgeRatio += q.RadiusEdgeRatio
+		s.MaxRadiusEdgeRatio = math.Max(s.MaxRadiusEdgeRatio, q.RadiusEdgeRatio)
+		s.MinArea = math.Min(s.MinArea, q.Area)
+		s.MaxArea = math.Max(s.MaxArea, q.Area)
+		s.MeanArea += q.Area
+		if q.MinAngle < SliverAngle {
+			s.Slivers++
+		}
+	}
+	n := float64(s.Triangles)
+	s.MeanAspectRatio /= n
+	s.MeanRadiusEdgeRatio /= n
+	s.MeanArea /= n
+	for _, q := range qualities {
+		d := q.Area - s.MeanArea
+		s.StdDevArea += d * d
+	}
+	s.StdDevArea = math.Sqrt(s.StdDevArea / n)
+	return qualities, s
+}
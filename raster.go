@@ -0,0 +1,105 @@
+package delaunay
+
+import (
+	"math"
+	"sync"
+)
+
+// Grid describes a raster of Width x Height square cells. Cell (x, y) covers
+// the square from Origin + (x, y) * CellSize to Origin + (x+1, y+1) *
+// CellSize and is stored at index y*Width + x.
+type Grid struct {
+	Origin        Point
+	CellSize      float64
+	Width, Height int
+}
+
+// Rasterize interpolates the per-point values linearly across each triangle
+// and samples them at the center of every grid cell. Cells whose centers lie
+// outside of the convex hull are set to nodata. If workers is greater than
+// one, the rows of the grid are split among that many goroutines.
+func (t *Triangulation) Rasterize(values []float64, grid Grid, nodata float64, workers int) []float64 {
+	result := make([]float64, grid.Width*grid.Height)
+	for i := range result {
+		result[i] = nodata
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	rows := (grid.Height + workers - 1) / workers
+	var wg sync.WaitGroup
+	for y0 := 0; y0 < grid.Height; y0 += rows {
+		y1 := y0 + rows
+		if y1 > grid.Height {
+			y1 = grid.Height
+		}
+		wg.Add(1)
+		go func(y0, y1 int) {
+			defer wg.Done()
+			t.rasterize(values, grid, y0, y1, result)
+		}(y0, y1)
+	}
+	wg.Wait()
+	return result
+}
+
+// rasterize scans the triangles over rows [y0, y1) of the grid
+func (t *Triangulation) rasterize(values []float64, grid Grid, y0, y1 int, result []float64) {
+	points := t.Points
+	ts := t.Triangles
+	size := grid.CellSize
+	for i := 0; i < len(ts); i += 3 {
+		// grid coordinates of the triangle, relative to cell centers
+		var p [3]Point
+		var v [3]float64
+		for j := 0; j < 3; j++ {
+			q := points[ts[i+j]].sub(grid.Origin).scale(1 / size)
+			p[j] = Point{q.X - 0.5, q.Y - 0.5}
+			v[j] = values[ts[i+j]]
+		}
+
+		// plane through the values
+		d := (p[1].X-p[0].X)*(p[2].Y-p[0].Y) - (p[2].X-p[0].X)*(p[1].Y-p[0].Y)
+		if d == 0 {
+			continue
+		}
+		a := ((v[1]-v[0])*(p[2].Y-p[0].Y) - (v[2]-v[0])*(p[1].Y-p[0].Y)) / d
+		b := ((v[2]-v[0])*(p[1].X-p[0].X) - (v[1]-v[0])*(p[2].X-p[0].X)) / d
+		c := v[0] - a*p[0].X - b*p[0].Y
+
+		// rows covered by the triangle
+		minY := math.Min(p[0].Y, math.Min(p[1].Y, p[2].Y))
+		maxY := math.Max(p[0].Y, math.Max(p[1].Y, p[2].Y))
+		ya := int(math.Max(math.Ceil(minY), float64(y0)))
+		yb := int(math.Min(math.Floor(maxY), float64(y1-1)))
+		for y := ya; y <= yb; y++ {
+			// intersect the row with the edges of the triangle
+			fy := float64(y)
+			xa := infinity
+			xb := -infinity
+			for j := 0; j < 3; j++ {
+				q := p[j]
+				r := p[(j+1)%3]
+				if (q.Y > fy) == (r.Y > fy) && q.Y != fy {
+					continue
+				}
+				if q.Y == r.Y {
+					xa = math.Min(xa, math.Min(q.X, r.X))
+					xb = math.Max(xb, math.Max(q.X, r.X))
+					continue
+				}
+				x := q.X + (fy-q.Y)*(r.X-q.X)/(r.Y-q.Y)
+				xa = math.Min(xa, x)
+				xb = math.Max(xb, x)
+			}
+			xa = math.Max(math.Ceil(xa), 0)
+			xb = math.Min(math.Floor(xb), float64(grid.Width-1))
+			if xa > xb {
+				continue
+			}
+			for x := int(xa); x <= int(xb); x++ {
+				result[y*grid.Width+x] = a*float64(x) + b*fy + c
+			}
+		}
+	}
+}
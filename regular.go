@@ -0,0 +1,367 @@
+package delaunay
+
+import (
+	"fmt"
+	"sort"
+)
+
+// regularTriangulator incrementally builds a regular (weighted Delaunay)
+// triangulation using the Bowyer-Watson algorithm. Like the
+// tetrahedralizer, every edge of the convex hull has a ghost triangle
+// connecting it to the vertex at infinity. With nil weights it builds an
+// ordinary Delaunay triangulation.
+type regularTriangulator struct {
+	points    []Point
+	weights   []float64
+	triangles []int
+	halfedges []int
+	dead      []bool
+	free      []int
+	mark      []int
+	stamp     int
+	last      int
+	seed      uint32
+	edges     map[int]int
+	created   []int
+}
+
+func newRegularTriangulator(points []Point, weights []float64) *regularTriangulator {
+	return &regularTriangulator{
+		points:  points,
+		weights: weights,
+		seed:    1,
+		edges:   make(map[int]int),
+	}
+}
+
+func (t *regularTriangulator) weight(i int) float64 {
+	if t.weights == nil {
+		return 0
+	}
+	return t.weights[i]
+}
+
+func (t *regularTriangulator) triangulate() error {
+	points := t.points
+	n := len(points)
+	if n == 0 {
+		return nil
+	}
+
+	// compute bounds
+	x0 := points[0].X
+	y0 := points[0].Y
+	x1 := points[0].X
+	y1 := points[0].Y
+	for _, p := range points {
+		x0 = minFloat(x0, p.X)
+		y0 = minFloat(y0, p.Y)
+		x1 = maxFloat(x1, p.X)
+		y1 = maxFloat(y1, p.Y)
+	}
+
+	var i0, i1, i2 int
+
+	// pick a seed point close to midpoint
+	m := Point{(x0 + x1) / 2, (y0 + y1) / 2}
+	minDist := infinity
+	for i, p := range points {
+		d := p.squaredDistance(m)
+		if d < minDist {
+			i0 = i
+			minDist = d
+		}
+	}
+
+	// find point closest to seed point
+	minDist = infinity
+	for i, p := range points {
+		d := p.squaredDistance(points[i0])
+		if d > 0 && d < minDist {
+			i1 = i
+			minDist = d
+		}
+	}
+
+	// find the third point which forms the smallest circumcircle
+	minRadius := infinity
+	for i, p := range points {
+		if orient2d(points[i0], points[i1], p) == 0 {
+			continue
+		}
+		r := circumradius(points[i0], points[i1], p)
+		if r < minRadius {
+			i2 = i
+			minRadius = r
+		}
+	}
+	if minRadius == infinity {
+		return fmt.Errorf("No Delaunay triangulation exists for this input.")
+	}
+
+	// swap the order of the seed points for positive orientation
+	if orient2d(points[i0], points[i1], points[i2]) < 0 {
+		i1, i2 = i2, i1
+	}
+	t.init(i0, i1, i2)
+
+	// insert the remaining points in order of their distance from the seed
+	center := circumcenter(points[i0], points[i1], points[i2])
+	ids := make([]int, n)
+	distances := make([]float64, n)
+	for i, p := range points {
+		ids[i] = i
+		distances[i] = p.squaredDistance(center)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return distances[ids[i]] < distances[ids[j]]
+	})
+	for _, i := range ids {
+		if i == i0 || i == i1 || i == i2 {
+			continue
+		}
+		t.insert(i)
+	}
+	return nil
+}
+
+// init creates the positively oriented seed triangle and its ghosts
+func (t *regularTriangulator) init(i0, i1, i2 int) {
+	seed := [3]int{i0, i1, i2}
+	t.addTriangle(seed)
+	for j := 0; j < 3; j++ {
+		t.addTriangle([3]int{seed[(j+1)%3], seed[j], infinite})
+	}
+	t.halfedges = computeHalfedges(t.triangles)
+}
+
+func (t *regularTriangulator) addTriangle(v [3]int) int {
+	var i int
+	if len(t.free) > 0 {
+		i = t.free[len(t.free)-1]
+		t.free = t.free[:len(t.free)-1]
+		t.dead[i] = false
+	} else {
+		i = len(t.dead)
+		t.triangles = append(t.triangles, 0, 0, 0)
+		t.halfedges = append(t.halfedges, -1, -1, -1)
+		t.dead = append(t.dead, false)
+		t.mark = append(t.mark, 0)
+	}
+	copy(t.triangles[i*3:i*3+3], v[:])
+	return i
+}
+
+func (t *regularTriangulator) link(a, b int) {
+	t.halfedges[a] = b
+	t.halfedges[b] = a
+}
+
+// ghostVertex returns the position of the vertex at infinity in triangle i,
+// or -1 for finite triangles
+func (t *regularTriangulator) ghostVertex(i int) int {
+	for j := 0; j < 3; j++ {
+		if t.triangles[i*3+j] == infinite {
+			return j
+		}
+	}
+	return -1
+}
+
+// orient returns the orientation of triangle i with vertex j replaced by p
+func (t *regularTriangulator) orient(i, j int, p Point) float64 {
+	var q [3]Point
+	for k := 0; k < 3; k++ {
+		if k == j {
+			q[k] = p
+		} else {
+			q[k] = t.points[t.triangles[i*3+k]]
+		}
+	}
+	return orient2d(q[0], q[1], q[2])
+}
+
+// conflict reports whether the weighted point p is in conflict with triangle
+// i; a ghost triangle is in conflict when p lies strictly outside of its hull
+// edge, or on the line through that edge and in conflict with the finite
+// triangle on the other side
+func (t *regularTriangulator) conflict(i int, p Point, w float64) bool {
+	if k := t.ghostVertex(i); k >= 0 {
+		o := t.orient(i, k, p)
+		if o != 0 {
+			return o > 0
+		}
+		return t.conflict(t.halfedges[i*3+(k+1)%3]/3, p, w)
+	}
+	v := t.triangles[i*3 : i*3+3]
+	a, b, c := t.points[v[0]], t.points[v[1]], t.points[v[2]]
+	return powerTest(a, b, c, p, t.weight(v[0]), t.weight(v[1]), t.weight(v[2]), w) > 0
+}
+
+func (t *regularTriangulator) random() int {
+	// xorshift, to break ties in the visibility walk
+	t.seed ^= t.seed << 13
+	t.seed ^= t.seed >> 17
+	t.seed ^= t.seed << 5
+	return int(t.seed % 3)
+}
+
+// locate walks from the last created triangle towards p and returns the
+// triangle that contains it, or a ghost triangle if p lies outside of the
+// convex hull
+func (t *regularTriangulator) locate(p Point) int {
+	i := t.last
+	for {
+		if k := t.ghostVertex(i); k >= 0 {
+			i = t.halfedges[i*3+(k+1)%3] / 3
+		}
+		moved := false
+		r := t.random()
+		for m := 0; m < 3; m++ {
+			j := (m + r) % 3
+			if t.orient(i, (j+2)%3, p) < 0 {
+				i = t.halfedges[i*3+j] / 3
+				moved = true
+				break
+			}
+		}
+		if !moved || t.ghostVertex(i) >= 0 {
+			return i
+		}
+	}
+}
+
+// insert adds point index to the triangulation and reports whether it was
+// added; duplicate and redundant points are skipped. The triangles created by
+// the insertion are left in t.created.
+func (t *regularTriangulator) insert(index int) bool {
+	p := t.points[index]
+	w := t.weight(index)
+	t.created = t.created[:0]
+	start := t.locate(p)
+	if !t.conflict(start, p, w) {
+		return false
+	}
+
+	// collect the triangles in conflict with p, and the edges on the
+	// boundary of that cavity; marks are +stamp for triangles in the cavity
+	// and -stamp for triangles that were tested and are not
+	t.stamp++
+	stamp := t.stamp
+	cavity := []int{start}
+	t.mark[start] = stamp
+	var boundary []int
+	for k := 0; k < len(cavity); k++ {
+		i := cavity[k]
+		for j := 0; j < 3; j++ {
+			u := t.halfedges[i*3+j] / 3
+			if t.mark[u] == stamp {
+				continue
+			}
+			if t.mark[u] != -stamp && t.conflict(u, p, w) {
+				t.mark[u] = stamp
+				cavity = append(cavity, u)
+				continue
+			}
+			t.mark[u] = -stamp
+			boundary = append(boundary, i*3+j)
+		}
+	}
+
+	// capture the boundary edges before the cavity is freed
+	type edge struct {
+		v       [3]int
+		j       int
+		outside int
+	}
+	edges := make([]edge, len(boundary))
+	for k, e := range boundary {
+		var v [3]int
+		copy(v[:], t.triangles[e-e%3:e-e%3+3])
+		v[(e+2)%3] = index
+		edges[k] = edge{v, e % 3, t.halfedges[e]}
+	}
+	for _, i := range cavity {
+		t.dead[i] = true
+		t.free = append(t.free, i)
+	}
+
+	// connect p to every boundary edge
+	for key := range t.edges {
+		delete(t.edges, key)
+	}
+	for _, e := range edges {
+		i := t.addTriangle(e.v)
+		t.link(i*3+e.j, e.outside)
+		for _, j := range []int{(e.j + 1) % 3, (e.j + 2) % 3} {
+			// the shared vertex of the two new triangles along this edge
+			key := e.v[(j+1)%3]
+			if key == index {
+				key = e.v[j]
+			}
+			if h, ok := t.edges[key]; ok {
+				t.link(i*3+j, h)
+			} else {
+				t.edges[key] = i*3 + j
+			}
+		}
+		t.created = append(t.created, i)
+		t.last = i
+	}
+	return true
+}
+
+// finite returns the finite triangles and their halfedges
+func (t *regularTriangulator) finite() ([]int, []int) {
+	ids := make([]int, len(t.dead))
+	count := 0
+	for i := range t.dead {
+		ids[i] = -1
+		if !t.dead[i] && t.ghostVertex(i) < 0 {
+			ids[i] = count
+			count++
+		}
+	}
+	triangles := make([]int, 0, count*3)
+	halfedges := make([]int, 0, count*3)
+	for i, id := range ids {
+		if id < 0 {
+			continue
+		}
+		for j := 0; j < 3; j++ {
+			triangles = append(triangles, t.triangles[i*3+j])
+			h := t.halfedges[i*3+j]
+			if u := ids[h/3]; u >= 0 {
+				halfedges = append(halfedges, u*3+h%3)
+			} else {
+				halfedges = append(halfedges, -1)
+			}
+		}
+	}
+	return triangles, halfedges
+}
+
+// convexHull walks the ghost triangles around the convex hull
+func (t *regularTriangulator) convexHull() []Point {
+	start := -1
+	for i := range t.dead {
+		if !t.dead[i] && t.ghostVertex(i) >= 0 {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return nil
+	}
+	var result []Point
+	i := start
+	for {
+		k := t.ghostVertex(i)
+		result = append(result, t.points[t.triangles[i*3+(k+1)%3]])
+		i = t.halfedges[i*3+(k+2)%3] / 3
+		if i == start {
+			break
+		}
+	}
+	return result
+}
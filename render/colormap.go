@@ -0,0 +1,51 @@
+package render
+
+import (
+	"image/color"
+	"math"
+)
+
+// Colormap maps a value in [0, 1] to a color. Values outside of that range
+// are clamped.
+type Colormap func(t float64) color.RGBA
+
+// NewColormap returns a Colormap that interpolates linearly between the
+// provided colors, which are spaced evenly over [0, 1].
+func NewColormap(colors ...color.RGBA) Colormap {
+	return func(t float64) color.RGBA {
+		if len(colors) == 0 {
+			return color.RGBA{}
+		}
+		if len(colors) == 1 || !(t > 0) {
+			return colors[0]
+		}
+		if t >= 1 {
+			return colors[len(colors)-1]
+		}
+		t *= float64(len(colors) - 1)
+		i := int(t)
+		f := t - float64(i)
+		a, b := colors[i], colors[i+1]
+		lerp := func(x, y uint8) uint8 {
+			return uint8(math.Round(float64(x) + (float64(y)-float64(x))*f))
+		}
+		return color.RGBA{lerp(a.R, b.R), lerp(a.G, b.G), lerp(a.B, b.B), lerp(a.A, b.A)}
+	}
+}
+
+// Viridis approximates matplotlib's perceptually uniform viridis colormap.
+var Viridis = NewColormap(
+	color.RGBA{68, 1, 84, 255},
+	color.RGBA{72, 40, 120, 255},
+	color.RGBA{62, 74, 137, 255},
+	color.RGBA{49, 104, 142, 255},
+	color.RGBA{38, 130, 142, 255},
+	color.RGBA{31, 158, 137, 255},
+	color.RGBA{53, 183, 121, 255},
+	color.RGBA{109, 205, 89, 255},
+	color.RGBA{180, 222, 44, 255},
+	color.RGBA{253, 231, 37, 255},
+)
+
+// Grayscale maps 0 to black and 1 to white.
+var Grayscale = NewColormap(color.RGBA{0, 0, 0, 255}, color.RGBA{255, 255, 255, 255})
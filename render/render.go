@@ -0,0 +1,312 @@
+// Package render draws triangulations into images using only the standard
+// library, for quick visual checks without a graphics dependency.
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"os"
+
+	"github.com/fogleman/delaunay"
+)
+
+// Options controls Render. Layers are drawn in the order filled triangles,
+// edges, hull, points; a layer whose color is nil is omitted.
+type Options struct {
+	// Width and Height are the size of the image in pixels. If Height is
+	// zero it is chosen to match the aspect ratio of the points.
+	Width, Height int
+
+	// Padding is the margin in pixels between the points and the border.
+	Padding float64
+
+	// Background fills the image, or leaves it transparent if nil.
+	Background color.Color
+
+	// Colormap maps normalized values in [0, 1] to the fill colors of the
+	// triangles.
+	Colormap Colormap
+
+	// TriangleValues holds one value per triangle; each triangle is filled
+	// with the color of its value. If nil, PointValues holds one value per
+	// point, which are interpolated across the triangles. If both are nil,
+	// triangles are filled with FillColor.
+	TriangleValues []float64
+	PointValues    []float64
+	FillColor      color.Color
+
+	EdgeColor  color.Color
+	EdgeWidth  float64
+	HullColor  color.Color
+	HullWidth  float64
+	PointColor color.Color
+	PointSize  float64 // radius in pixels
+}
+
+// DefaultOptions returns options that draw black edges, a red hull and black
+// points on a white background, with triangles colored by Viridis when
+// values are provided.
+func DefaultOptions() Options {
+	return Options{
+		Width:      800,
+		Padding:    10,
+		Background: color.White,
+		Colormap:   Viridis,
+		EdgeColor:  color.Black,
+		EdgeWidth:  1,
+		HullColor:  color.RGBA{255, 0, 0, 255},
+		HullWidth:  2,
+		PointColor: color.Black,
+		PointSize:  2,
+	}
+}
+
+// Render draws the triangulation into a new image. The drawing is fitted to
+// the bounding box of the points, with the y axis pointing up. Lines and
+// points are antialiased. An error is returned if TriangleValues or
+// PointValues do not have one value per triangle or per point.
+func Render(t *delaunay.Triangulation, options Options) (*image.RGBA, error) {
+	points := t.Points
+	ts := t.Triangles
+	if tv := options.TriangleValues; tv != nil && len(tv) != len(ts)/3 {
+		return nil, fmt.Errorf("expected %d triangle values, got %d", len(ts)/3, len(tv))
+	}
+	if pv := options.PointValues; pv != nil && len(pv) != len(points) {
+		return nil, fmt.Errorf("expected %d point values, got %d", len(points), len(pv))
+	}
+
+	// fit the bounding box of the points into the image
+	min := delaunay.Point{X: math.Inf(1), Y: math.Inf(1)}
+	max := delaunay.Point{X: math.Inf(-1), Y: math.Inf(-1)}
+	for _, p := range points {
+		min = delaunay.Point{X: math.Min(min.X, p.X), Y: math.Min(min.Y, p.Y)}
+		max = delaunay.Point{X: math.Max(max.X, p.X), Y: math.Max(max.Y, p.Y)}
+	}
+	if len(points) == 0 {
+		min, max = delaunay.Point{}, delaunay.Point{}
+	}
+	dx := max.X - min.X
+	dy := max.Y - min.Y
+	pad := options.Padding
+	width := options.Width
+	height := options.Height
+	if width <= 0 {
+		width = 800
+	}
+	if height <= 0 {
+		if dx > 0 && dy > 0 {
+			height = int(math.Round((float64(width)-2*pad)*dy/dx + 2*pad))
+		} else {
+			height = width
+		}
+	}
+	w := float64(width)
+	h := float64(height)
+	scale := math.Inf(1)
+	if dx > 0 {
+		scale = (w - 2*pad) / dx
+	}
+	if dy > 0 {
+		scale = math.Min(scale, (h-2*pad)/dy)
+	}
+	if math.IsInf(scale, 1) {
+		scale = 1
+	}
+	ox := (w - dx*scale) / 2
+	oy := (h - dy*scale) / 2
+	project := func(p delaunay.Point) delaunay.Point {
+		return delaunay.Point{X: ox + (p.X-min.X)*scale, Y: h - oy - (p.Y-min.Y)*scale}
+	}
+	projected := make([]delaunay.Point, len(points))
+	for i, p := range points {
+		projected[i] = project(p)
+	}
+
+	im := image.NewRGBA(image.Rect(0, 0, width, height))
+	if options.Background != nil {
+		draw.Draw(im, im.Bounds(), image.NewUniform(options.Background), image.Point{}, draw.Src)
+	}
+
+	// filled triangles
+	colormap := options.Colormap
+	if colormap == nil {
+		colormap = Viridis
+	}
+	if tv := options.TriangleValues; tv != nil {
+		lo, hi := valueRange(tv)
+		for i := 0; i < len(ts); i += 3 {
+			c := colormap(normalize(tv[i/3], lo, hi))
+			fillTriangle(im, projected[ts[i]], projected[ts[i+1]], projected[ts[i+2]],
+				func(wa, wb, wc float64) color.Color { return c })
+		}
+	} else if pv := options.PointValues; pv != nil {
+		lo, hi := valueRange(pv)
+		for i := 0; i < len(ts); i += 3 {
+			va := normalize(pv[ts[i]], lo, hi)
+			vb := normalize(pv[ts[i+1]], lo, hi)
+			vc := normalize(pv[ts[i+2]], lo, hi)
+			fillTriangle(im, projected[ts[i]], projected[ts[i+1]], projected[ts[i+2]],
+				func(wa, wb, wc float64) color.Color { return colormap(wa*va + wb*vb + wc*vc) })
+		}
+	} else if c := options.FillColor; c != nil {
+		for i := 0; i < len(ts); i += 3 {
+			fillTriangle(im, projected[ts[i]], projected[ts[i+1]], projected[ts[i+2]],
+				func(wa, wb, wc float64) color.Color { return c })
+		}
+	}
+
+	// unique edges
+	if c := options.EdgeColor; c != nil {
+		for i, j := range t.Halfedges {
+			if i > j {
+				next := i + 1
+				if i%3 == 2 {
+					next = i - 2
+				}
+				drawLine(im, projected[ts[i]], projected[ts[next]], options.EdgeWidth, c)
+			}
+		}
+	}
+
+	// convex hull
+	if c := options.HullColor; c != nil {
+		hull := t.ConvexHull
+		for i, p := range hull {
+			q := hull[(i+1)%len(hull)]
+			drawLine(im, project(p), project(q), options.HullWidth, c)
+		}
+	}
+
+	// points
+	if c := options.PointColor; c != nil {
+		for _, p := range projected {
+			drawDisk(im, p, options.PointSize, c)
+		}
+	}
+
+	return im, nil
+}
+
+// SavePNG writes the image to a PNG file.
+func SavePNG(path string, im image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, im); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func valueRange(values []float64) (float64, float64) {
+	lo := math.Inf(1)
+	hi := math.Inf(-1)
+	for _, v := range values {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	return lo, hi
+}
+
+func normalize(v, lo, hi float64) float64 {
+	if !(hi > lo) {
+		return 0.5
+	}
+	return (v - lo) / (hi - lo)
+}
+
+// blend composites c over the pixel at x, y with the given coverage
+func blend(im *image.RGBA, x, y int, c color.Color, coverage float64) {
+	if !(image.Point{x, y}.In(im.Rect)) || coverage <= 0 {
+		return
+	}
+	if coverage > 1 {
+		coverage = 1
+	}
+	r, g, b, a := c.RGBA()
+	sa := float64(a) / 0xffff * coverage
+	i := im.PixOffset(x, y)
+	pix := im.Pix[i : i+4 : i+4]
+	for k, s := range [4]uint32{r, g, b, a} {
+		v := float64(s)/0xffff*coverage*255 + float64(pix[k])*(1-sa)
+		pix[k] = uint8(math.Min(math.Round(v), 255))
+	}
+}
+
+// fillTriangle fills the pixels whose centers lie inside of the triangle,
+// coloring each from its barycentric coordinates
+func fillTriangle(im *image.RGBA, a, b, c delaunay.Point, shade func(wa, wb, wc float64) color.Color) {
+	d := (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X)
+	if d == 0 {
+		return
+	}
+	x0 := int(math.Max(math.Floor(math.Min(a.X, math.Min(b.X, c.X))), float64(im.Rect.Min.X)))
+	y0 := int(math.Max(math.Floor(math.Min(a.Y, math.Min(b.Y, c.Y))), float64(im.Rect.Min.Y)))
+	x1 := int(math.Min(math.Ceil(math.Max(a.X, math.Max(b.X, c.X))), float64(im.Rect.Max.X-1)))
+	y1 := int(math.Min(math.Ceil(math.Max(a.Y, math.Max(b.Y, c.Y))), float64(im.Rect.Max.Y-1)))
+	for y := y0; y <= y1; y++ {
+		py := float64(y) + 0.5
+		for x := x0; x <= x1; x++ {
+			px := float64(x) + 0.5
+			wa := ((b.X-px)*(c.Y-py) - (b.Y-py)*(c.X-px)) / d
+			wb := ((c.X-px)*(a.Y-py) - (c.Y-py)*(a.X-px)) / d
+			wc := 1 - wa - wb
+			if wa < 0 || wb < 0 || wc < 0 {
+				continue
+			}
+			blend(im, x, y, shade(wa, wb, wc), 1)
+		}
+	}
+}
+
+// drawLine draws an antialiased line of the given width, with the coverage
+// of each pixel estimated from the distance of its center to the segment
+func drawLine(im *image.RGBA, p, q delaunay.Point, width float64, c color.Color) {
+	r := width / 2
+	x0 := int(math.Floor(math.Min(p.X, q.X) - r - 1))
+	y0 := int(math.Floor(math.Min(p.Y, q.Y) - r - 1))
+	x1 := int(math.Ceil(math.Max(p.X, q.X) + r + 1))
+	y1 := int(math.Ceil(math.Max(p.Y, q.Y) + r + 1))
+	x0, y0 = maxInt(x0, im.Rect.Min.X), maxInt(y0, im.Rect.Min.Y)
+	x1, y1 = minInt(x1, im.Rect.Max.X-1), minInt(y1, im.Rect.Max.Y-1)
+	dx := q.X - p.X
+	dy := q.Y - p.Y
+	l2 := dx*dx + dy*dy
+	for y := y0; y <= y1; y++ {
+		py := float64(y) + 0.5
+		for x := x0; x <= x1; x++ {
+			px := float64(x) + 0.5
+			s := 0.0
+			if l2 > 0 {
+				s = math.Max(0, math.Min(1, ((px-p.X)*dx+(py-p.Y)*dy)/l2))
+			}
+			d := math.Hypot(px-(p.X+s*dx), py-(p.Y+s*dy))
+			blend(im, x, y, c, r+0.5-d)
+		}
+	}
+}
+
+// drawDisk draws an antialiased disk
+func drawDisk(im *image.RGBA, p delaunay.Point, r float64, c color.Color) {
+	drawLine(im, p, p, 2*r, c)
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
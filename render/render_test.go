@@ -0,0 +1,74 @@
+package render
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/fogleman/delaunay"
+)
+
+func TestRender(t *testing.T) {
+	points := []delaunay.Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: 1}}
+	tri, err := delaunay.Triangulate(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options := DefaultOptions()
+	options.Width = 100
+	options.PointValues = []float64{0, 0, 1, 1}
+	im, err := Render(tri, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := im.Bounds(); b.Dx() != 100 || b.Dy() != 100 {
+		t.Fatalf("unexpected size: %v", b)
+	}
+
+	// y points up, so the bottom of the image has the lowest values; the
+	// value of a pixel is the y coordinate of its center
+	if c := im.RGBAAt(50, 85); c != Viridis((90-85.5)/80) {
+		t.Fatalf("unexpected fill color near the bottom: %v", c)
+	}
+	if c := im.RGBAAt(30, 15); c != Viridis((90-15.5)/80) {
+		t.Fatalf("unexpected fill color near the top: %v", c)
+	}
+
+	// the hull runs along the padding, with antialiased edges
+	if c := im.RGBAAt(50, 10); c.R != 255 || c.G != 0 {
+		t.Fatalf("expected the hull at the top, got %v", c)
+	}
+	if c := im.RGBAAt(50, 12); c.R == 255 || c.R == 0 {
+		t.Fatalf("expected a partially covered pixel, got %v", c)
+	}
+	if c := im.RGBAAt(5, 5); c != (color.RGBA{255, 255, 255, 255}) {
+		t.Fatalf("expected the background, got %v", c)
+	}
+
+	// flat colors per triangle
+	options = DefaultOptions()
+	options.Width = 100
+	options.EdgeColor = nil
+	options.TriangleValues = []float64{1, 2}
+	options.Colormap = Grayscale
+	im, err = Render(tri, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := im.RGBAAt(30, 50)
+	b := im.RGBAAt(70, 50)
+	if a == b || (a != Grayscale(0) && a != Grayscale(1)) || (b != Grayscale(0) && b != Grayscale(1)) {
+		t.Fatalf("unexpected triangle colors: %v %v", a, b)
+	}
+
+	// values must match the triangles and points
+	options.TriangleValues = []float64{1}
+	if _, err := Render(tri, options); err == nil {
+		t.Fatal("expected an error for too few triangle values")
+	}
+	options.TriangleValues = nil
+	options.PointValues = []float64{1, 2}
+	if _, err := Render(tri, options); err == nil {
+		t.Fatal("expected an error for too few point values")
+	}
+}
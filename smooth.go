@@ -0,0 +1,197 @@
+package delaunay
+
+import (
+	"fmt"
+	"math"
+)
+
+// SmoothingMethod selects how Smooth relocates points.
+type SmoothingMethod int
+
+const (
+	// LaplacianSmoothing moves each point to the centroid of its neighbors.
+	LaplacianSmoothing SmoothingMethod = iota
+
+	// AngleBasedSmoothing moves each point towards the bisectors of the
+	// angles that its neighbors form with their own neighbors around it,
+	// which improves angles more directly than Laplacian smoothing.
+	AngleBasedSmoothing
+
+	// ODTSmoothing moves each point to the area-weighted average of the
+	// circumcenters of its triangles, which minimizes the interpolation
+	// error of an optimal Delaunay triangulation.
+	ODTSmoothing
+)
+
+// Smooth improves the shape of the triangles by repeatedly relocating the
+// points that are not on the convex hull and not marked in fixed, which is
+// either nil or holds one flag per point. A point is only moved if none of
+// its triangles would be inverted. After each iteration, edges are flipped
+// until the triangulation is Delaunay again. Smooth modifies the Points
+// slice, which is the slice that was passed to Triangulate, as well as
+// Triangles and Halfedges.
+func (t *Triangulation) Smooth(method SmoothingMethod, iterations int, fixed []bool) error {
+	points := t.Points
+	if fixed != nil && len(fixed) != len(points) {
+		return fmt.Errorf("expected %d fixed flags, got %d", len(points), len(fixed))
+	}
+	ts := t.Triangles
+	hs := t.Halfedges
+
+	// points on the convex hull never move
+	locked := make([]bool, len(points))
+	for e, h := range hs {
+		if h < 0 {
+			locked[ts[e]] = true
+		}
+	}
+	for i := range fixed {
+		locked[i] = locked[i] || fixed[i]
+	}
+
+	edges := make([]int, len(points))
+	var ring []int
+	for iteration := 0; iteration < iterations; iteration++ {
+		// find one outgoing halfedge for each point
+		for i := range edges {
+			edges[i] = -1
+		}
+		for e, i := range ts {
+			edges[i] = e
+		}
+
+		for i, start := range edges {
+			if start < 0 || locked[i] {
+				continue
+			}
+
+			// collect the outgoing halfedges in order around the point;
+			// triangle j of the ring has the neighbors j and j+1
+			ring = ring[:0]
+			e := start
+			for {
+				ring = append(ring, e)
+				e = hs[prevHalfedge(e)]
+				if e == start {
+					break
+				}
+			}
+			neighbor := func(j int) Point {
+				j = (j + len(ring)) % len(ring)
+				return points[ts[nextHalfedge(ring[j])]]
+			}
+
+			var target Point
+			switch method {
+			case LaplacianSmoothing:
+				for j := range ring {
+					target = target.add(neighbor(j))
+				}
+				target = target.scale(1 / float64(len(ring)))
+			case AngleBasedSmoothing:
+				p := points[i]
+				for j := range ring {
+					q := neighbor(j)
+					a := neighbor(j - 1).sub(q)
+					b := neighbor(j + 1).sub(q)
+					d := a.scale(1 / a.length()).add(b.scale(1 / b.length()))
+					if d.length() == 0 {
+						target = target.add(p)
+						continue
+					}
+					target = target.add(q.add(d.scale(p.distance(q) / d.length())))
+				}
+				target = target.scale(1 / float64(len(ring)))
+			case ODTSmoothing:
+				var total float64
+				for j := range ring {
+					a := points[i]
+					b := neighbor(j)
+					c := neighbor(j + 1)
+					w := area(a, b, c)
+					target = target.add(circumcenter(a, b, c).scale(w))
+					total += w
+				}
+				target = target.scale(1 / total)
+			}
+
+			// back off towards the original position until no triangle
+			// would be inverted
+			p := points[i]
+			for k := 0; k < 8; k++ {
+				if math.IsNaN(target.X) || math.IsNaN(target.Y) {
+					break
+				}
+				valid := true
+				for j := range ring {
+					if orient2d(target, neighbor(j), neighbor(j+1)) <= 0 {
+						valid = false
+						break
+					}
+				}
+				if valid {
+					points[i] = target
+					break
+				}
+				target = p.add(target).scale(0.5)
+			}
+		}
+
+		t.legalize()
+	}
+	return nil
+}
+
+// legalize flips edges until every edge satisfies the Delaunay condition
+func (t *Triangulation) legalize() {
+	points := t.Points
+	ts := t.Triangles
+	hs := t.Halfedges
+	for pass := 0; pass < len(ts); pass++ {
+		flipped := false
+		for a, b := range hs {
+			if b < 0 {
+				continue
+			}
+			ar := prevHalfedge(a)
+			al := nextHalfedge(a)
+			bl := prevHalfedge(b)
+			p0 := points[ts[ar]]
+			pr := points[ts[a]]
+			pl := points[ts[al]]
+			p1 := points[ts[bl]]
+			if powerTest(p0, pr, pl, p1, 0, 0, 0, 0) <= 0 {
+				continue
+			}
+			t.flip(a)
+			flipped = true
+		}
+		if !flipped {
+			return
+		}
+	}
+}
+
+// flip replaces the edge of halfedge a with the other diagonal of the
+// quadrilateral formed by its two triangles, like in the triangulator's
+// legalize
+func (t *Triangulation) flip(a int) {
+	ts := t.Triangles
+	hs := t.Halfedges
+	b := hs[a]
+	ar := prevHalfedge(a)
+	bl := prevHalfedge(b)
+	hbl := hs[bl]
+	har := hs[ar]
+	ts[a] = ts[bl]
+	ts[b] = ts[ar]
+	link := func(a, b int) {
+		hs[a] = b
+		if b >= 0 {
+			hs[b] = a
+		}
+	}
+	link(a, hbl)
+	link(b, har)
+	link(ar, bl)
+}
@@ -0,0 +1,177 @@
+package delaunay
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+)
+
+// Solid returns a watertight triangle mesh of the solid between the surface
+// given by the elevation z of each point and the horizontal plane at base,
+// which must not be above any elevation. The mesh consists of the
+// triangulation as the top surface, vertical walls along the boundary of the
+// triangulation and a flat bottom. Vertex i is point i at its elevation and
+// vertex i+len(Points) is point i at the base. Each triangle winds
+// counter-clockwise when seen from outside of the solid, so that its normal
+// points outwards.
+func (t *Triangulation) Solid(z []float64, base float64) ([]Vector, []int, error) {
+	points := t.Points
+	ts := t.Triangles
+	hs := t.Halfedges
+	n := len(points)
+	if len(z) != n {
+		return nil, nil, fmt.Errorf("expected %d elevations, got %d", n, len(z))
+	}
+	for _, h := range z {
+		if h < base {
+			return nil, nil, fmt.Errorf("base %g is above elevation %g", base, h)
+		}
+	}
+
+	vertices := make([]Vector, 2*n)
+	for i, p := range points {
+		vertices[i] = Vector{p.X, p.Y, z[i]}
+		vertices[i+n] = Vector{p.X, p.Y, base}
+	}
+
+	triangles := make([]int, 0, 2*len(ts)+6*(len(t.ConvexHull)+1))
+	for i := 0; i < len(ts)/3; i++ {
+		// the top faces upwards and the bottom downwards
+		c := t.corners(i)
+		triangles = append(triangles, c[0], c[1], c[2])
+		triangles = append(triangles, c[0]+n, c[2]+n, c[1]+n)
+	}
+	for e, h := range hs {
+		if h >= 0 {
+			continue
+		}
+		// the outside of the triangulation is on the left of hull halfedges
+		a := ts[e]
+		b := ts[nextHalfedge(e)]
+		triangles = append(triangles, a, b, b+n)
+		triangles = append(triangles, a, b+n, a+n)
+	}
+	return vertices, triangles, nil
+}
+
+// WriteSTL writes the solid described by Solid as a binary STL file.
+func (t *Triangulation) WriteSTL(w io.Writer, z []float64, base float64) error {
+	vertices, triangles, err := t.Solid(z, base)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	var header [80]byte
+	copy(header[:], "delaunay")
+	bw.Write(header[:])
+	binary.Write(bw, binary.LittleEndian, uint32(len(triangles)/3))
+	var record [50]byte
+	for i := 0; i < len(triangles); i += 3 {
+		a := vertices[triangles[i]]
+		b := vertices[triangles[i+1]]
+		c := vertices[triangles[i+2]]
+		normal := b.sub(a).cross(c.sub(a))
+		if l := normal.length(); l > 0 {
+			normal = normal.mulScalar(1 / l)
+		}
+		for j, v := range []Vector{normal, a, b, c} {
+			for k, x := range []float64{v.X, v.Y, v.Z} {
+				binary.LittleEndian.PutUint32(record[j*12+k*4:], math.Float32bits(float32(x)))
+			}
+		}
+		bw.Write(record[:])
+	}
+	return bw.Flush()
+}
+
+// WriteGLB writes the solid described by Solid as a binary glTF 2.0 file
+// holding a single mesh. glTF uses a y-up coordinate system, so the point
+// (x, y, z) is written as (x, z, -y).
+func (t *Triangulation) WriteGLB(w io.Writer, z []float64, base float64) error {
+	vertices, triangles, err := t.Solid(z, base)
+	if err != nil {
+		return err
+	}
+
+	// binary buffer: positions followed by indices
+	var bin bytes.Buffer
+	min := []float64{infinity, infinity, infinity}
+	max := []float64{-infinity, -infinity, -infinity}
+	for _, v := range vertices {
+		for k, x := range []float64{v.X, v.Z, -v.Y} {
+			f := float32(x)
+			binary.Write(&bin, binary.LittleEndian, f)
+			min[k] = math.Min(min[k], float64(f))
+			max[k] = math.Max(max[k], float64(f))
+		}
+	}
+	positionsLength := bin.Len()
+	for _, i := range triangles {
+		binary.Write(&bin, binary.LittleEndian, uint32(i))
+	}
+	indicesLength := bin.Len() - positionsLength
+	for bin.Len()%4 != 0 {
+		bin.WriteByte(0)
+	}
+
+	const (
+		arrayBuffer        = 34962
+		elementArrayBuffer = 34963
+		floatType          = 5126
+		unsignedIntType    = 5125
+		trianglesMode      = 4
+	)
+	type m = map[string]interface{}
+	document := m{
+		"asset":  m{"version": "2.0", "generator": "delaunay"},
+		"scene":  0,
+		"scenes": []m{{"nodes": []int{0}}},
+		"nodes":  []m{{"mesh": 0}},
+		"meshes": []m{{
+			"primitives": []m{{
+				"attributes": m{"POSITION": 0},
+				"indices":    1,
+				"mode":       trianglesMode,
+			}},
+		}},
+		"buffers": []m{{"byteLength": bin.Len()}},
+		"bufferViews": []m{
+			{"buffer": 0, "byteOffset": 0, "byteLength": positionsLength, "target": arrayBuffer},
+			{"buffer": 0, "byteOffset": positionsLength, "byteLength": indicesLength, "target": elementArrayBuffer},
+		},
+		"accessors": []m{
+			{"bufferView": 0, "componentType": floatType, "count": len(vertices), "type": "VEC3", "min": min, "max": max},
+			{"bufferView": 1, "componentType": unsignedIntType, "count": len(triangles), "type": "SCALAR"},
+		},
+	}
+	if len(vertices) == 0 {
+		// min and max must be finite
+		accessors := document["accessors"].([]m)
+		accessors[0]["min"] = []float64{0, 0, 0}
+		accessors[0]["max"] = []float64{0, 0, 0}
+	}
+	js, err := json.Marshal(document)
+	if err != nil {
+		return err
+	}
+	for len(js)%4 != 0 {
+		js = append(js, ' ')
+	}
+
+	bw := bufio.NewWriter(w)
+	le := binary.LittleEndian
+	binary.Write(bw, le, uint32(0x46546C67)) // "glTF"
+	binary.Write(bw, le, uint32(2))
+	binary.Write(bw, le, uint32(12+8+len(js)+8+bin.Len()))
+	binary.Write(bw, le, uint32(len(js)))
+	binary.Write(bw, le, uint32(0x4E4F534A)) // "JSON"
+	bw.Write(js)
+	binary.Write(bw, le, uint32(bin.Len()))
+	binary.Write(bw, le, uint32(0x004E4942)) // "BIN"
+	bw.Write(bin.Bytes())
+	return bw.Flush()
+}
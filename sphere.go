@@ -0,0 +1,205 @@
+package delaunay
+
+import (
+	"fmt"
+	"math"
+)
+
+// SphericalTriangulation is a Delaunay triangulation of points on the unit
+// sphere. It has the same Triangles and Halfedges layout as Triangulation,
+// but the triangulation is closed: every halfedge has a twin and there is no
+// convex hull. Triangles are counter-clockwise when viewed from outside the
+// sphere.
+type SphericalTriangulation struct {
+	Points    []Vector
+	Triangles []int
+	Halfedges []int
+}
+
+// LonLatToVector converts a point with X = longitude and Y = latitude, in
+// degrees, to a unit vector.
+func LonLatToVector(p Point) Vector {
+	lon := p.X * math.Pi / 180
+	lat := p.Y * math.Pi / 180
+	c := math.Cos(lat)
+	return Vector{c * math.Cos(lon), c * math.Sin(lon), math.Sin(lat)}
+}
+
+// VectorToLonLat converts a vector to a point with X = longitude and
+// Y = latitude, in degrees.
+func VectorToLonLat(v Vector) Point {
+	lon := math.Atan2(v.Y, v.X) * 180 / math.Pi
+	lat := math.Atan2(v.Z, math.Hypot(v.X, v.Y)) * 180 / math.Pi
+	return Point{lon, lat}
+}
+
+// TriangulateLonLat returns a spherical Delaunay triangulation of the provided
+// points, which have X = longitude and Y = latitude in degrees.
+func TriangulateLonLat(points []Point) (*SphericalTriangulation, error) {
+	vectors := make([]Vector, len(points))
+	for i, p := range points {
+		vectors[i] = LonLatToVector(p)
+	}
+	return TriangulateSphere(vectors)
+}
+
+// TriangulateSphere returns a spherical Delaunay triangulation of the provided
+// points, which are projected onto the unit sphere.
+func TriangulateSphere(points []Vector) (*SphericalTriangulation, error) {
+	n := len(points)
+	vectors := make([]Vector, n)
+	for i, p := range points {
+		if p.length() == 0 {
+			return nil, fmt.Errorf("point %d has zero length", i)
+		}
+		vectors[i] = p.normalize()
+	}
+	if n < 4 {
+		return nil, fmt.Errorf("No spherical Delaunay triangulation exists for this input.")
+	}
+
+	// build an orthonormal basis with the first point as the pole
+	pole := vectors[0]
+	u := Vector{1, 0, 0}
+	if math.Abs(pole.X) > 0.9 {
+		u = Vector{0, 1, 0}
+	}
+	u = u.sub(pole.mulScalar(u.dot(pole))).normalize()
+	w := pole.cross(u)
+
+	// stereographic projection from the pole onto the plane; points that
+	// coincide with the pole have no projection and are skipped
+	projected := make([]Point, 0, n-1)
+	ids := make([]int, 0, n-1)
+	for i := 1; i < n; i++ {
+		v := vectors[i]
+		d := 1 - v.dot(pole)
+		if d < eps {
+			continue
+		}
+		projected = append(projected, Point{v.dot(u) / d, v.dot(w) / d})
+		ids = append(ids, i)
+	}
+
+	planar, err := Triangulate(projected)
+	if err != nil {
+		return nil, err
+	}
+
+	// map the planar triangles back to input indices and close the hole
+	// left by the pole with a fan of triangles across the hull edges
+	triangles := make([]int, 0, len(planar.Triangles)*2)
+	for _, i := range planar.Triangles {
+		triangles = append(triangles, ids[i])
+	}
+	for e, h := range planar.Halfedges {
+		if h >= 0 {
+			continue
+		}
+		a := ids[planar.Triangles[e]]
+		b := ids[planar.Triangles[nextHalfedge(e)]]
+		triangles = append(triangles, b, a, 0)
+	}
+
+	// make the triangles counter-clockwise when viewed from outside
+	if len(triangles) > 0 {
+		a := vectors[triangles[0]]
+		b := vectors[triangles[1]]
+		c := vectors[triangles[2]]
+		if b.sub(a).cross(c.sub(a)).dot(a) < 0 {
+			for i := 0; i < len(triangles); i += 3 {
+				triangles[i+1], triangles[i+2] = triangles[i+2], triangles[i+1]
+			}
+		}
+	}
+
+	halfedges := computeHalfedges(triangles)
+	return &SphericalTriangulation{vectors, triangles, halfedges}, nil
+}
+
+func (t *SphericalTriangulation) circumcenter(i int) Vector {
+	a := t.Points[t.Triangles[i]]
+	b := t.Points[t.Triangles[i+1]]
+	c := t.Points[t.Triangles[i+2]]
+	return b.sub(a).cross(c.sub(a)).normalize()
+}
+
+// VoronoiCells returns the spherical Voronoi cell of each point as a
+// counter-clockwise polygon of unit vectors, viewed from outside the sphere.
+// Points that are not part of the triangulation (duplicates) have nil cells.
+func (t *SphericalTriangulation) VoronoiCells() [][]Vector {
+	centers := make([]Vector, len(t.Triangles)/3)
+	for i := range centers {
+		centers[i] = t.circumcenter(i * 3)
+	}
+
+	// find one outgoing halfedge for each point
+	edges := make([]int, len(t.Points))
+	for i := range edges {
+		edges[i] = -1
+	}
+	for e, i := range t.Triangles {
+		if edges[i] < 0 {
+			edges[i] = e
+		}
+	}
+
+	cells := make([][]Vector, len(t.Points))
+	for i, start := range edges {
+		if start < 0 {
+			continue
+		}
+		var cell []Vector
+		e := start
+		for {
+			cell = append(cell, centers[e/3])
+			e = t.Halfedges[prevHalfedge(e)]
+			if e == start || e < 0 {
+				break
+			}
+		}
+		cells[i] = cell
+	}
+	return cells
+}
+
+// Validate performs several sanity checks on the SphericalTriangulation to
+// check for potential errors. Returns nil if no issues were found.
+func (t *SphericalTriangulation) Validate() error {
+	// verify halfedges
+	for i1, i2 := range t.Halfedges {
+		if i2 < 0 || t.Halfedges[i2] != i1 {
+			return fmt.Errorf("invalid halfedge connection")
+		}
+	}
+
+	// verify euler characteristic of a closed surface
+	used := make(map[int]bool)
+	for _, i := range t.Triangles {
+		used[i] = true
+	}
+	if len(t.Triangles)/3 != 2*len(used)-4 {
+		return fmt.Errorf("triangulation is not closed: %d triangles, %d points",
+			len(t.Triangles)/3, len(used))
+	}
+
+	// verify orientation and the empty circumcircle property for each edge
+	for i := 0; i < len(t.Triangles); i += 3 {
+		a := t.Points[t.Triangles[i+0]]
+		b := t.Points[t.Triangles[i+1]]
+		c := t.Points[t.Triangles[i+2]]
+		normal := b.sub(a).cross(c.sub(a))
+		if normal.dot(a) < -1e-9 {
+			return fmt.Errorf("triangle %d is inverted", i/3)
+		}
+		for j := 0; j < 3; j++ {
+			h := t.Halfedges[i+j]
+			p := t.Points[t.Triangles[prevHalfedge(h)]]
+			if normal.dot(p.sub(a)) > 1e-9 {
+				return fmt.Errorf("triangle %d is not delaunay", i/3)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,207 @@
+package delaunay
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// SVGStyle describes how one layer of an SVG drawing is painted. Empty
+// colors are written as "none".
+type SVGStyle struct {
+	Fill        string
+	Stroke      string
+	StrokeWidth float64
+	Radius      float64 // only used for points
+}
+
+// SVGOptions controls WriteSVG. Layers whose style is nil are omitted; they
+// are drawn in the order Voronoi, Triangles, Edges, Hull, Points.
+type SVGOptions struct {
+	// Width and Height are the size of the image in pixels. If Height is
+	// zero it is chosen to match the aspect ratio of the points.
+	Width, Height float64
+
+	// Padding is the margin in pixels between the points and the border.
+	Padding float64
+
+	// Background is the background color, or empty for none.
+	Background string
+
+	// Precision is the number of decimals written for coordinates.
+	Precision int
+
+	Voronoi   *SVGStyle
+	Triangles *SVGStyle
+	Edges     *SVGStyle
+	Hull      *SVGStyle
+	Points    *SVGStyle
+}
+
+// DefaultSVGOptions returns options that draw the edges, the convex hull and
+// the points on a white background.
+func DefaultSVGOptions() SVGOptions {
+	return SVGOptions{
+		Width:      800,
+		Padding:    10,
+		Background: "white",
+		Precision:  2,
+		Edges:      &SVGStyle{Stroke: "black", StrokeWidth: 1},
+		Hull:       &SVGStyle{Stroke: "red", StrokeWidth: 2},
+		Points:     &SVGStyle{Fill: "black", Radius: 2},
+	}
+}
+
+// WriteSVG draws the triangulation as an SVG image. The drawing is fitted to
+// the bounding box of the points, with the y axis pointing up, and Voronoi
+// cells are clipped to that bounding box.
+func (t *Triangulation) WriteSVG(w io.Writer, options SVGOptions) error {
+	points := t.Points
+	ts := t.Triangles
+	hs := t.Halfedges
+
+	// fit the bounding box of the points into the image
+	min := Point{infinity, infinity}
+	max := Point{-infinity, -infinity}
+	for _, p := range points {
+		min = Point{math.Min(min.X, p.X), math.Min(min.Y, p.Y)}
+		max = Point{math.Max(max.X, p.X), math.Max(max.Y, p.Y)}
+	}
+	if len(points) == 0 {
+		min, max = Point{}, Point{}
+	}
+	dx := max.X - min.X
+	dy := max.Y - min.Y
+	pad := options.Padding
+	width := options.Width
+	height := options.Height
+	if width <= 0 {
+		width = 800
+	}
+	if height <= 0 {
+		if dx > 0 && dy > 0 {
+			height = math.Round((width-2*pad)*dy/dx + 2*pad)
+		} else {
+			height = width
+		}
+	}
+	scale := infinity
+	if dx > 0 {
+		scale = (width - 2*pad) / dx
+	}
+	if dy > 0 {
+		scale = math.Min(scale, (height-2*pad)/dy)
+	}
+	if scale == infinity {
+		scale = 1
+	}
+	ox := (width - dx*scale) / 2
+	oy := (height - dy*scale) / 2
+
+	bw := bufio.NewWriter(w)
+	number := func(x float64) string {
+		return strconv.FormatFloat(x, 'f', options.Precision, 64)
+	}
+	project := func(p Point) Point {
+		return Point{ox + (p.X-min.X)*scale, height - oy - (p.Y-min.Y)*scale}
+	}
+	coords := func(p Point) string {
+		p = project(p)
+		return number(p.X) + "," + number(p.Y)
+	}
+	path := func(polygons [][]Point, closed bool) string {
+		var d []byte
+		for _, polygon := range polygons {
+			for j, p := range polygon {
+				if len(d) > 0 {
+					d = append(d, ' ')
+				}
+				if j == 0 {
+					d = append(d, 'M')
+				} else {
+					d = append(d, 'L')
+				}
+				d = append(d, coords(p)...)
+			}
+			if closed && len(polygon) > 0 {
+				d = append(d, " Z"...)
+			}
+		}
+		return string(d)
+	}
+	attrs := func(s *SVGStyle) string {
+		fill := s.Fill
+		if fill == "" {
+			fill = "none"
+		}
+		stroke := s.Stroke
+		if stroke == "" {
+			stroke = "none"
+		}
+		result := fmt.Sprintf(` fill="%s" stroke="%s"`, escapeAttr(fill), escapeAttr(stroke))
+		if s.Stroke != "" {
+			result += fmt.Sprintf(` stroke-width="%s" stroke-linejoin="round"`, number(s.StrokeWidth))
+		}
+		return result
+	}
+
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %s %s">`+"\n",
+		number(width), number(height), number(width), number(height))
+	if options.Background != "" {
+		fmt.Fprintf(bw, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", escapeAttr(options.Background))
+	}
+
+	if s := options.Voronoi; s != nil {
+		var polygons [][]Point
+		for _, cell := range t.VoronoiCells(min, max) {
+			if len(cell) > 0 {
+				polygons = append(polygons, cell)
+			}
+		}
+		fmt.Fprintf(bw, `<path class="voronoi"%s d="%s"/>`+"\n", attrs(s), path(polygons, true))
+	}
+
+	if s := options.Triangles; s != nil {
+		polygons := make([][]Point, 0, len(ts)/3)
+		for i := 0; i < len(ts); i += 3 {
+			polygons = append(polygons, []Point{points[ts[i]], points[ts[i+1]], points[ts[i+2]]})
+		}
+		fmt.Fprintf(bw, `<path class="triangles"%s d="%s"/>`+"\n", attrs(s), path(polygons, true))
+	}
+
+	if s := options.Edges; s != nil {
+		var lines [][]Point
+		for i, h := range hs {
+			if i > h {
+				lines = append(lines, []Point{points[ts[i]], points[ts[nextHalfedge(i)]]})
+			}
+		}
+		fmt.Fprintf(bw, `<path class="edges"%s d="%s"/>`+"\n", attrs(s), path(lines, false))
+	}
+
+	if s := options.Hull; s != nil && len(t.ConvexHull) > 0 {
+		fmt.Fprintf(bw, `<path class="hull"%s d="%s"/>`+"\n", attrs(s), path([][]Point{t.ConvexHull}, true))
+	}
+
+	if s := options.Points; s != nil {
+		fmt.Fprintf(bw, `<g class="points"%s>`+"\n", attrs(s))
+		for _, p := range points {
+			p = project(p)
+			fmt.Fprintf(bw, `<circle cx="%s" cy="%s" r="%s"/>`+"\n", number(p.X), number(p.Y), number(s.Radius))
+		}
+		fmt.Fprintln(bw, `</g>`)
+	}
+
+	fmt.Fprintln(bw, `</svg>`)
+	return bw.Flush()
+}
+
+func escapeAttr(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
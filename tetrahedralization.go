@@ -0,0 +1,62 @@
+package delaunay
+
+import "fmt"
+
+// Tetrahedralization is a three-dimensional Delaunay tetrahedralization.
+// Tetrahedra holds four point indices per tetrahedron, positively oriented.
+// Halffaces is laid out like Halfedges: entry 4*t+j refers to the face of
+// tetrahedron t opposite its j-th vertex, and holds the index of the same face
+// as seen from the adjacent tetrahedron, or -1 on the convex hull.
+type Tetrahedralization struct {
+	Points     []Vector
+	Tetrahedra []int
+	Halffaces  []int
+}
+
+// Tetrahedralize returns a Delaunay tetrahedralization of the provided points.
+func Tetrahedralize(points []Vector) (*Tetrahedralization, error) {
+	t := newTetrahedralizer(points)
+	err := t.tetrahedralize()
+	tetrahedra, halffaces := t.finite()
+	return &Tetrahedralization{points, tetrahedra, halffaces}, err
+}
+
+// Validate performs several sanity checks on the Tetrahedralization to check
+// for potential errors, including the empty circumsphere property of every
+// pair of adjacent tetrahedra. Returns nil if no issues were found.
+func (t *Tetrahedralization) Validate() error {
+	// verify halffaces
+	for f1, f2 := range t.Halffaces {
+		if f2 != -1 && t.Halffaces[f2] != f1 {
+			return fmt.Errorf("invalid halfface connection")
+		}
+	}
+
+	ts := t.Tetrahedra
+	points := t.Points
+	for i := 0; i < len(ts); i += 4 {
+		a := points[ts[i+0]]
+		b := points[ts[i+1]]
+		c := points[ts[i+2]]
+		d := points[ts[i+3]]
+
+		// verify orientation
+		if orient3d(a, b, c, d) <= 0 {
+			return fmt.Errorf("tetrahedron %d is not positively oriented", i/4)
+		}
+
+		// verify that no neighboring point lies inside the circumsphere
+		for j := 0; j < 4; j++ {
+			f := t.Halffaces[i+j]
+			if f < 0 {
+				continue
+			}
+			p := points[ts[f]]
+			if insphere(a, b, c, d, p) > 0 {
+				return fmt.Errorf("tetrahedron %d is not delaunay", i/4)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,384 @@
+package delaunay
+
+import (
+	"fmt"
+	"sort"
+)
+
+// infinite is the index of the vertex at infinity; every face of the convex
+// hull has a ghost tetrahedron connecting it to this vertex, which keeps the
+// tetrahedralization closed while points are inserted
+const infinite = -1
+
+type tetrahedralizer struct {
+	points     []Vector
+	tetrahedra []int
+	neighbors  []int
+	dead       []bool
+	free       []int
+	mark       []int
+	stamp      int
+	last       int
+	seed       uint32
+	edges      map[[2]int]int
+}
+
+func newTetrahedralizer(points []Vector) *tetrahedralizer {
+	return &tetrahedralizer{points: points, seed: 1, edges: make(map[[2]int]int)}
+}
+
+func (t *tetrahedralizer) tetrahedralize() error {
+	points := t.points
+	n := len(points)
+	if n == 0 {
+		return nil
+	}
+
+	// compute bounds
+	min := points[0]
+	max := points[0]
+	for _, p := range points {
+		min = Vector{minFloat(min.X, p.X), minFloat(min.Y, p.Y), minFloat(min.Z, p.Z)}
+		max = Vector{maxFloat(max.X, p.X), maxFloat(max.Y, p.Y), maxFloat(max.Z, p.Z)}
+	}
+
+	// pick a seed point close to the center
+	var i0, i1, i2, i3 int
+	m := min.add(max).mulScalar(0.5)
+	minDist := infinity
+	for i, p := range points {
+		d := p.squaredDistance(m)
+		if d < minDist {
+			i0 = i
+			minDist = d
+		}
+	}
+
+	// find the point closest to the seed point
+	minDist = infinity
+	for i, p := range points {
+		d := p.squaredDistance(points[i0])
+		if d > 0 && d < minDist {
+			i1 = i
+			minDist = d
+		}
+	}
+
+	// find the third point which forms the smallest triangle perimeter that
+	// isn't degenerate
+	minDist = infinity
+	a := points[i0]
+	b := points[i1]
+	for i, p := range points {
+		if b.sub(a).cross(p.sub(a)).length() == 0 {
+			continue
+		}
+		d := p.squaredDistance(a) + p.squaredDistance(b)
+		if d < minDist {
+			i2 = i
+			minDist = d
+		}
+	}
+
+	// find the fourth point closest to the seed triangle but not coplanar
+	minDist = infinity
+	c := points[i2]
+	for i, p := range points {
+		if orient3d(a, b, c, p) == 0 {
+			continue
+		}
+		d := p.squaredDistance(a) + p.squaredDistance(b) + p.squaredDistance(c)
+		if d < minDist {
+			i3 = i
+			minDist = d
+		}
+	}
+	if minDist == infinity {
+		return fmt.Errorf("No Delaunay tetrahedralization exists for this input.")
+	}
+
+	// swap the order of the seed points for positive orientation
+	if orient3d(a, b, c, points[i3]) < 0 {
+		i2, i3 = i3, i2
+	}
+
+	// build the seed tetrahedron and a ghost tetrahedron for each of its faces
+	seed := [4]int{i0, i1, i2, i3}
+	t.addTetrahedron(seed)
+	for j := 0; j < 4; j++ {
+		ghost := seed
+		ghost[j] = infinite
+		k, l := (j+1)%4, (j+2)%4
+		ghost[k], ghost[l] = ghost[l], ghost[k]
+		t.addTetrahedron(ghost)
+	}
+	t.linkSeed()
+
+	// insert the remaining points in order of their distance from the seed
+	center := a.add(b).add(c).add(points[i3]).mulScalar(0.25)
+	ids := make([]int, n)
+	distances := make([]float64, n)
+	for i, p := range points {
+		ids[i] = i
+		distances[i] = p.squaredDistance(center)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return distances[ids[i]] < distances[ids[j]]
+	})
+	for _, i := range ids {
+		if i == i0 || i == i1 || i == i2 || i == i3 {
+			continue
+		}
+		t.insert(i)
+	}
+	return nil
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxFloat(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func (t *tetrahedralizer) addTetrahedron(v [4]int) int {
+	var i int
+	if len(t.free) > 0 {
+		i = t.free[len(t.free)-1]
+		t.free = t.free[:len(t.free)-1]
+		t.dead[i] = false
+	} else {
+		i = len(t.dead)
+		t.tetrahedra = append(t.tetrahedra, 0, 0, 0, 0)
+		t.neighbors = append(t.neighbors, -1, -1, -1, -1)
+		t.dead = append(t.dead, false)
+		t.mark = append(t.mark, 0)
+	}
+	copy(t.tetrahedra[i*4:i*4+4], v[:])
+	return i
+}
+
+func (t *tetrahedralizer) link(a, b int) {
+	t.neighbors[a] = b
+	t.neighbors[b] = a
+}
+
+// linkSeed connects the faces of the seed and ghost tetrahedra
+func (t *tetrahedralizer) linkSeed() {
+	faces := make(map[[3]int]int)
+	for f := range t.neighbors {
+		var key [3]int
+		k := 0
+		for j := 0; j < 4; j++ {
+			if j != f%4 {
+				key[k] = t.tetrahedra[f-f%4+j]
+				k++
+			}
+		}
+		sort.Ints(key[:])
+		if g, ok := faces[key]; ok {
+			t.link(f, g)
+		} else {
+			faces[key] = f
+		}
+	}
+}
+
+func (t *tetrahedralizer) isGhost(i int) bool {
+	v := t.tetrahedra[i*4 : i*4+4]
+	return v[0] == infinite || v[1] == infinite || v[2] == infinite || v[3] == infinite
+}
+
+// orient returns the orientation of tetrahedron i with vertex j replaced by p
+func (t *tetrahedralizer) orient(i, j int, p Vector) float64 {
+	var q [4]Vector
+	for k := 0; k < 4; k++ {
+		if k == j {
+			q[k] = p
+		} else {
+			q[k] = t.points[t.tetrahedra[i*4+k]]
+		}
+	}
+	return orient3d(q[0], q[1], q[2], q[3])
+}
+
+// conflict reports whether p lies strictly inside the circumsphere of
+// tetrahedron i; a ghost tetrahedron is in conflict when p lies strictly
+// outside of its hull face, or on the plane of that face and in conflict with
+// the finite tetrahedron on the other side
+func (t *tetrahedralizer) conflict(i int, p Vector) bool {
+	v := t.tetrahedra[i*4 : i*4+4]
+	for j, k := range v {
+		if k != infinite {
+			continue
+		}
+		o := t.orient(i, j, p)
+		if o != 0 {
+			return o > 0
+		}
+		return t.conflict(t.neighbors[i*4+j]/4, p)
+	}
+	a := t.points[v[0]]
+	b := t.points[v[1]]
+	c := t.points[v[2]]
+	d := t.points[v[3]]
+	return insphere(a, b, c, d, p) > 0
+}
+
+func (t *tetrahedralizer) random() int {
+	// xorshift, to break ties in the visibility walk
+	t.seed ^= t.seed << 13
+	t.seed ^= t.seed >> 17
+	t.seed ^= t.seed << 5
+	return int(t.seed % 4)
+}
+
+// locate walks from the last created tetrahedron towards p and returns the
+// tetrahedron that contains it, or a ghost tetrahedron if p lies outside of
+// the convex hull
+func (t *tetrahedralizer) locate(p Vector) int {
+	i := t.last
+	for {
+		if t.isGhost(i) {
+			for j := 0; j < 4; j++ {
+				if t.tetrahedra[i*4+j] == infinite {
+					i = t.neighbors[i*4+j] / 4
+					break
+				}
+			}
+		}
+		moved := false
+		r := t.random()
+		for k := 0; k < 4; k++ {
+			j := (k + r) % 4
+			if t.orient(i, j, p) < 0 {
+				i = t.neighbors[i*4+j] / 4
+				moved = true
+				break
+			}
+		}
+		if !moved || t.isGhost(i) {
+			return i
+		}
+	}
+}
+
+func (t *tetrahedralizer) insert(index int) {
+	p := t.points[index]
+	start := t.locate(p)
+	if !t.conflict(start, p) {
+		// duplicate point; skip it
+		return
+	}
+
+	// collect the tetrahedra whose circumspheres contain p, and the faces on
+	// the boundary of that cavity; marks are +stamp for tetrahedra in the
+	// cavity and -stamp for tetrahedra that were tested and are not
+	t.stamp++
+	stamp := t.stamp
+	cavity := []int{start}
+	t.mark[start] = stamp
+	var boundary []int
+	for k := 0; k < len(cavity); k++ {
+		i := cavity[k]
+		for j := 0; j < 4; j++ {
+			u := t.neighbors[i*4+j] / 4
+			if t.mark[u] == stamp {
+				continue
+			}
+			if t.mark[u] != -stamp && t.conflict(u, p) {
+				t.mark[u] = stamp
+				cavity = append(cavity, u)
+				continue
+			}
+			t.mark[u] = -stamp
+			boundary = append(boundary, i*4+j)
+		}
+	}
+
+	// capture the boundary faces before the cavity is freed
+	type face struct {
+		v       [4]int
+		j       int
+		outside int
+	}
+	faces := make([]face, len(boundary))
+	for k, f := range boundary {
+		var v [4]int
+		copy(v[:], t.tetrahedra[f-f%4:f-f%4+4])
+		v[f%4] = index
+		faces[k] = face{v, f % 4, t.neighbors[f]}
+	}
+	for _, i := range cavity {
+		t.dead[i] = true
+		t.free = append(t.free, i)
+	}
+
+	// connect p to every boundary face
+	for key := range t.edges {
+		delete(t.edges, key)
+	}
+	for _, f := range faces {
+		i := t.addTetrahedron(f.v)
+		t.link(i*4+f.j, f.outside)
+		for j := 0; j < 4; j++ {
+			if j == f.j {
+				continue
+			}
+			var key [2]int
+			k := 0
+			for l := 0; l < 4; l++ {
+				if l != j && l != f.j {
+					key[k] = f.v[l]
+					k++
+				}
+			}
+			if key[0] > key[1] {
+				key[0], key[1] = key[1], key[0]
+			}
+			if g, ok := t.edges[key]; ok {
+				t.link(i*4+j, g)
+			} else {
+				t.edges[key] = i*4 + j
+			}
+		}
+		t.last = i
+	}
+}
+
+// finite returns the finite tetrahedra and their face adjacency
+func (t *tetrahedralizer) finite() ([]int, []int) {
+	ids := make([]int, len(t.dead))
+	count := 0
+	for i := range t.dead {
+		ids[i] = -1
+		if !t.dead[i] && !t.isGhost(i) {
+			ids[i] = count
+			count++
+		}
+	}
+	tetrahedra := make([]int, 0, count*4)
+	halffaces := make([]int, 0, count*4)
+	for i, id := range ids {
+		if id < 0 {
+			continue
+		}
+		for j := 0; j < 4; j++ {
+			tetrahedra = append(tetrahedra, t.tetrahedra[i*4+j])
+			f := t.neighbors[i*4+j]
+			if u := ids[f/4]; u >= 0 {
+				halffaces = append(halffaces, u*4+f%4)
+			} else {
+				halffaces = append(halffaces, -1)
+			}
+		}
+	}
+	return tetrahedra, halffaces
+}
@@ -0,0 +1,503 @@
+package delaunay
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// This file reads and writes the file formats of Jonathan Shewchuk's
+// Triangle program. Readers accept both zero-based and one-based files by
+// looking at the number of the first record, and writers produce one-based
+// files like Triangle does by default. Triangle lists the corners of each
+// triangle counter-clockwise, while Triangles here winds clockwise, so the
+// order of the corners is reversed in .ele files.
+
+// TriangleNodes holds the contents of a .node file.
+type TriangleNodes struct {
+	Points     []Point
+	Attributes [][]float64 // attributes of each point, or nil
+	Markers    []int       // boundary marker of each point, or nil
+}
+
+// TrianglePoly holds the contents of a .poly file: a planar straight line
+// graph of points, segments between them and holes. This package has no
+// constrained triangulation, so segments, holes and regions are only read
+// and written. If Points is empty, the points are in a separate .node file.
+type TrianglePoly struct {
+	TriangleNodes
+	Segments       [][2]int
+	SegmentMarkers []int // boundary marker of each segment, or nil
+	Holes          []Point
+	Regions        []TriangleRegion
+}
+
+// TriangleRegion is a regional attribute and area constraint of a .poly file.
+type TriangleRegion struct {
+	Point     Point
+	Attribute float64
+	MaxArea   float64
+}
+
+type triangleReader struct {
+	scanner *bufio.Scanner
+	line    int
+	base    int // -1 until the first record has been read
+}
+
+func newTriangleReader(r io.Reader) *triangleReader {
+	return &triangleReader{scanner: bufio.NewScanner(r), base: -1}
+}
+
+// next returns the fields of the next line that is not empty or a comment
+func (r *triangleReader) next(min int) ([]string, error) {
+	for r.scanner.Scan() {
+		r.line++
+		text := r.scanner.Text()
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < min {
+			return nil, r.errorf("expected %d fields, got %d", min, len(fields))
+		}
+		return fields, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.ErrUnexpectedEOF
+}
+
+// record returns the fields of the next numbered record, without its number
+func (r *triangleReader) record(min int) ([]string, error) {
+	fields, err := r.next(min + 1)
+	if err != nil {
+		return nil, err
+	}
+	if r.base < 0 {
+		base, err := strconv.Atoi(fields[0])
+		if err != nil || (base != 0 && base != 1) {
+			return nil, r.errorf("invalid first record number: %q", fields[0])
+		}
+		r.base = base
+	}
+	return fields[1:], nil
+}
+
+func (r *triangleReader) errorf(format string, a ...interface{}) error {
+	return fmt.Errorf("line %d: %s", r.line, fmt.Sprintf(format, a...))
+}
+
+func (r *triangleReader) ints(fields []string) ([]int, error) {
+	result := make([]int, len(fields))
+	for i, field := range fields {
+		x, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, r.errorf("%v", err)
+		}
+		result[i] = x
+	}
+	return result, nil
+}
+
+func (r *triangleReader) floats(fields []string) ([]float64, error) {
+	result := make([]float64, len(fields))
+	for i, field := range fields {
+		x, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return nil, r.errorf("%v", err)
+		}
+		result[i] = x
+	}
+	return result, nil
+}
+
+// indices converts point numbers to zero-based indices; point numbers that
+// are -1 (no point) are kept as -1
+func (r *triangleReader) indices(fields []string) ([]int, error) {
+	result, err := r.ints(fields)
+	if err != nil {
+		return nil, err
+	}
+	for i, x := range result {
+		if x >= 0 {
+			result[i] = x - r.base
+		}
+	}
+	return result, nil
+}
+
+// header reads a header line of counts, filling in the missing trailing
+// fields from the defaults and ignoring any fields beyond them
+func (r *triangleReader) header(defaults ...int) ([]int, error) {
+	fields, err := r.next(1)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) > len(defaults) {
+		fields = fields[:len(defaults)]
+	}
+	header, err := r.ints(fields)
+	if err != nil {
+		return nil, err
+	}
+	for _, x := range header {
+		if x < 0 {
+			return nil, r.errorf("invalid header: %v", header)
+		}
+	}
+	return withDefaults(header, defaults...), nil
+}
+
+// withDefaults fills in the missing trailing fields of a header line
+func withDefaults(header []int, defaults ...int) []int {
+	for len(header) < len(defaults) {
+		header = append(header, defaults[len(header)])
+	}
+	return header
+}
+
+// readNodes reads the header and the points of a .node or .poly file
+func (r *triangleReader) readNodes() (TriangleNodes, error) {
+	var nodes TriangleNodes
+	header, err := r.header(0, 2, 0, 0)
+	if err != nil {
+		return nodes, err
+	}
+	n, dim, attributes, markers := header[0], header[1], header[2], header[3]
+	if dim != 2 {
+		return nodes, r.errorf("unsupported dimension: %d", dim)
+	}
+	for i := 0; i < n; i++ {
+		fields, err := r.record(2 + attributes + markers)
+		if err != nil {
+			return nodes, err
+		}
+		values, err := r.floats(fields[:2+attributes])
+		if err != nil {
+			return nodes, err
+		}
+		nodes.Points = append(nodes.Points, Point{values[0], values[1]})
+		if attributes > 0 {
+			nodes.Attributes = append(nodes.Attributes, values[2:])
+		}
+		if markers > 0 {
+			m, err := r.ints(fields[2+attributes : 3+attributes])
+			if err != nil {
+				return nodes, err
+			}
+			nodes.Markers = append(nodes.Markers, m[0])
+		}
+	}
+	return nodes, nil
+}
+
+// ReadNode reads a .node file.
+func ReadNode(r io.Reader) (*TriangleNodes, error) {
+	nodes, err := newTriangleReader(r).readNodes()
+	if err != nil {
+		return nil, err
+	}
+	return &nodes, nil
+}
+
+// ReadPoly reads a .poly file. The regional attributes section is optional.
+func ReadPoly(r io.Reader) (*TrianglePoly, error) {
+	tr := newTriangleReader(r)
+	nodes, err := tr.readNodes()
+	if err != nil {
+		return nil, err
+	}
+	poly := &TrianglePoly{TriangleNodes: nodes}
+
+	header, err := tr.header(0, 0)
+	if err != nil {
+		return nil, err
+	}
+	n, markers := header[0], header[1]
+	for i := 0; i < n; i++ {
+		fields, err := tr.record(2 + markers)
+		if err != nil {
+			return nil, err
+		}
+		s, err := tr.indices(fields[:2])
+		if err != nil {
+			return nil, err
+		}
+		poly.Segments = append(poly.Segments, [2]int{s[0], s[1]})
+		if markers > 0 {
+			m, err := tr.ints(fields[2:3])
+			if err != nil {
+				return nil, err
+			}
+			poly.SegmentMarkers = append(poly.SegmentMarkers, m[0])
+		}
+	}
+
+	header, err = tr.header(0)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < header[0]; i++ {
+		fields, err := tr.record(2)
+		if err != nil {
+			return nil, err
+		}
+		p, err := tr.floats(fields[:2])
+		if err != nil {
+			return nil, err
+		}
+		poly.Holes = append(poly.Holes, Point{p[0], p[1]})
+	}
+
+	header, err = tr.header(0)
+	if err == io.ErrUnexpectedEOF {
+		return poly, nil
+	} else if err != nil {
+		return nil, err
+	}
+	for i := 0; i < header[0]; i++ {
+		fields, err := tr.record(3)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, "-1")
+		v, err := tr.floats(fields[:4])
+		if err != nil {
+			return nil, err
+		}
+		poly.Regions = append(poly.Regions, TriangleRegion{Point{v[0], v[1]}, v[2], v[3]})
+	}
+	return poly, nil
+}
+
+// ReadEle reads a .ele file of triangles between the provided points, which
+// are typically read from the matching .node file, and returns them as a
+// Triangulation with recomputed Halfedges and ConvexHull. Only the three
+// corners of each triangle are read; attributes and the extra nodes of
+// second order triangles are ignored.
+func ReadEle(r io.Reader, points []Point) (*Triangulation, error) {
+	tr := newTriangleReader(r)
+	header, err := tr.header(0)
+	if err != nil {
+		return nil, err
+	}
+	var triangles []int
+	for i := 0; i < header[0]; i++ {
+		fields, err := tr.record(3)
+		if err != nil {
+			return nil, err
+		}
+		v, err := tr.indices(fields[:3])
+		if err != nil {
+			return nil, err
+		}
+		triangles = append(triangles, v...)
+	}
+	return newMeshTriangulation(points, triangles)
+}
+
+// ReadNeigh reads a .neigh file. Neighbor j of triangle i is the triangle
+// across from its corner j, or -1 if there is none.
+func ReadNeigh(r io.Reader) ([][3]int, error) {
+	tr := newTriangleReader(r)
+	header, err := tr.header(0)
+	if err != nil {
+		return nil, err
+	}
+	var result [][3]int
+	for i := 0; i < header[0]; i++ {
+		fields, err := tr.record(3)
+		if err != nil {
+			return nil, err
+		}
+		v, err := tr.indices(fields[:3])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, [3]int{v[0], v[1], v[2]})
+	}
+	return result, nil
+}
+
+// ReadEdge reads a .edge file, returning the edges and their boundary
+// markers, which are nil if the file has none.
+func ReadEdge(r io.Reader) ([][2]int, []int, error) {
+	tr := newTriangleReader(r)
+	header, err := tr.header(0, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	n, markers := header[0], header[1]
+	var edges [][2]int
+	var result []int
+	for i := 0; i < n; i++ {
+		fields, err := tr.record(2 + markers)
+		if err != nil {
+			return nil, nil, err
+		}
+		v, err := tr.indices(fields[:2])
+		if err != nil {
+			return nil, nil, err
+		}
+		edges = append(edges, [2]int{v[0], v[1]})
+		if markers > 0 {
+			m, err := tr.ints(fields[2:3])
+			if err != nil {
+				return nil, nil, err
+			}
+			result = append(result, m[0])
+		}
+	}
+	return edges, result, nil
+}
+
+func writeNodes(w *bufio.Writer, nodes *TriangleNodes) {
+	attributes := 0
+	if len(nodes.Attributes) > 0 {
+		attributes = len(nodes.Attributes[0])
+	}
+	markers := 0
+	if nodes.Markers != nil {
+		markers = 1
+	}
+	fmt.Fprintf(w, "%d 2 %d %d\n", len(nodes.Points), attributes, markers)
+	for i, p := range nodes.Points {
+		fmt.Fprintf(w, "%d %s %s", i+1, formatFloat(p.X), formatFloat(p.Y))
+		if attributes > 0 {
+			for _, a := range nodes.Attributes[i] {
+				fmt.Fprintf(w, " %s", formatFloat(a))
+			}
+		}
+		if markers > 0 {
+			fmt.Fprintf(w, " %d", nodes.Markers[i])
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// WriteNode writes a .node file.
+func WriteNode(w io.Writer, nodes *TriangleNodes) error {
+	bw := bufio.NewWriter(w)
+	writeNodes(bw, nodes)
+	return bw.Flush()
+}
+
+// WritePoly writes a .poly file.
+func WritePoly(w io.Writer, poly *TrianglePoly) error {
+	bw := bufio.NewWriter(w)
+	writeNodes(bw, &poly.TriangleNodes)
+	markers := 0
+	if poly.SegmentMarkers != nil {
+		markers = 1
+	}
+	fmt.Fprintf(bw, "%d %d\n", len(poly.Segments), markers)
+	for i, s := range poly.Segments {
+		fmt.Fprintf(bw, "%d %d %d", i+1, s[0]+1, s[1]+1)
+		if markers > 0 {
+			fmt.Fprintf(bw, " %d", poly.SegmentMarkers[i])
+		}
+		fmt.Fprintln(bw)
+	}
+	fmt.Fprintf(bw, "%d\n", len(poly.Holes))
+	for i, p := range poly.Holes {
+		fmt.Fprintf(bw, "%d %s %s\n", i+1, formatFloat(p.X), formatFloat(p.Y))
+	}
+	if len(poly.Regions) > 0 {
+		fmt.Fprintf(bw, "%d\n", len(poly.Regions))
+		for i, r := range poly.Regions {
+			fmt.Fprintf(bw, "%d %s %s %s %s\n", i+1, formatFloat(r.Point.X), formatFloat(r.Point.Y),
+				formatFloat(r.Attribute), formatFloat(r.MaxArea))
+		}
+	}
+	return bw.Flush()
+}
+
+// WriteNode writes the points of the triangulation as a .node file, with
+// boundary marker 1 for points on the boundary and 0 for the others.
+func (t *Triangulation) WriteNode(w io.Writer) error {
+	markers := make([]int, len(t.Points))
+	for e, h := range t.Halfedges {
+		if h < 0 {
+			markers[t.Triangles[e]] = 1
+		}
+	}
+	return WriteNode(w, &TriangleNodes{Points: t.Points, Markers: markers})
+}
+
+// WriteEle writes the triangles as a .ele file.
+func (t *Triangulation) WriteEle(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	n := len(t.Triangles) / 3
+	fmt.Fprintf(bw, "%d 3 0\n", n)
+	for i := 0; i < n; i++ {
+		c := t.corners(i)
+		fmt.Fprintf(bw, "%d %d %d %d\n", i+1, c[0]+1, c[1]+1, c[2]+1)
+	}
+	return bw.Flush()
+}
+
+// Neighbors returns the neighbors of each triangle in the order of a .neigh
+// file: neighbor j of triangle i is the triangle across from corner j of
+// triangle i in its .ele file, or -1 if there is none.
+func (t *Triangulation) Neighbors() [][3]int {
+	hs := t.Halfedges
+	result := make([][3]int, len(hs)/3)
+	for i := range result {
+		// the edge across from each of the corners starts at the corner
+		// that follows it in Triangles
+		for j, e := range [3]int{i*3 + 1, i*3 + 0, i*3 + 2} {
+			result[i][j] = -1
+			if hs[e] >= 0 {
+				result[i][j] = hs[e] / 3
+			}
+		}
+	}
+	return result
+}
+
+// WriteNeigh writes the neighbors of each triangle as a .neigh file.
+func (t *Triangulation) WriteNeigh(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	neighbors := t.Neighbors()
+	fmt.Fprintf(bw, "%d 3\n", len(neighbors))
+	for i, n := range neighbors {
+		fmt.Fprintf(bw, "%d", i+1)
+		for _, j := range n {
+			if j >= 0 {
+				j++
+			}
+			fmt.Fprintf(bw, " %d", j)
+		}
+		fmt.Fprintln(bw)
+	}
+	return bw.Flush()
+}
+
+// WriteEdge writes the unique edges of the triangulation as a .edge file,
+// with boundary marker 1 for edges on the boundary and 0 for the others.
+func (t *Triangulation) WriteEdge(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	ts := t.Triangles
+	var lines []string
+	for i, h := range t.Halfedges {
+		if i > h {
+			marker := 0
+			if h < 0 {
+				marker = 1
+			}
+			lines = append(lines, fmt.Sprintf("%d %d %d", ts[i]+1, ts[nextHalfedge(i)]+1, marker))
+		}
+	}
+	fmt.Fprintf(bw, "%d 1\n", len(lines))
+	for i, line := range lines {
+		fmt.Fprintf(bw, "%d %s\n", i+1, line)
+	}
+	return bw.Flush()
+}
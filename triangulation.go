@@ -36,6 +36,12 @@ func (t *Triangulation) area() float64 {
 // potential errors. Returns nil if no issues were found. You normally
 // shouldn't need to call this function but it can be useful for debugging.
 func (t *Triangulation) Validate() error {
+	return t.validate(1e-9, 1e-9)
+}
+
+// validate is Validate with the tolerances used to compare the hull areas
+// and perimeters
+func (t *Triangulation) validate(areaTolerance, lengthTolerance float64) error {
 	// verify halfedges
 	for i1, i2 := range t.Halfedges {
 		if i1 != -1 && t.Halfedges[i1] != i2 {
@@ -52,14 +58,14 @@ func (t *Triangulation) Validate() error {
 	area1 := polygonArea(hull1)
 	area2 := polygonArea(hull2)
 	area3 := t.area()
-	if math.Abs(area1-area2) > 1e-9 || math.Abs(area1-area3) > 1e-9 {
+	if math.Abs(area1-area2) > areaTolerance || math.Abs(area1-area3) > areaTolerance {
 		return fmt.Errorf("hull areas disagree: %f, %f, %f", area1, area2, area3)
 	}
 
 	// verify convex hull perimeter
 	perimeter1 := polygonPerimeter(hull1)
 	perimeter2 := polygonPerimeter(hull2)
-	if math.Abs(perimeter1-perimeter2) > 1e-9 {
+	if math.Abs(perimeter1-perimeter2) > lengthTolerance {
 		return fmt.Errorf("hull perimeters disagree: %f, %f", perimeter1, perimeter2)
 	}
 
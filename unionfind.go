@@ -0,0 +1,39 @@
+package delaunay
+
+type unionFind struct {
+	parent []int
+	size   []int
+}
+
+func newUnionFind(n int) *unionFind {
+	u := &unionFind{make([]int, n), make([]int, n)}
+	for i := range u.parent {
+		u.parent[i] = i
+		u.size[i] = 1
+	}
+	return u
+}
+
+func (u *unionFind) find(i int) int {
+	for u.parent[i] != i {
+		u.parent[i] = u.parent[u.parent[i]]
+		i = u.parent[i]
+	}
+	return i
+}
+
+// union merges the sets containing i and j and reports whether they were
+// previously disjoint
+func (u *unionFind) union(i, j int) bool {
+	i = u.find(i)
+	j = u.find(j)
+	if i == j {
+		return false
+	}
+	if u.size[i] < u.size[j] {
+		i, j = j, i
+	}
+	u.parent[j] = i
+	u.size[i] += u.size[j]
+	return true
+}
@@ -73,6 +73,24 @@ func circumcenter(a, b, c Point) Point {
 	return Point{x, y}
 }
 
+// powerCenter returns the center of the circle orthogonal to the three
+// weighted points, which is the circumcenter when all weights are equal
+func powerCenter(a, b, c Point, wa, wb, wc float64) Point {
+	dx := b.X - a.X
+	dy := b.Y - a.Y
+	ex := c.X - a.X
+	ey := c.Y - a.Y
+
+	bl := dx*dx + dy*dy - wb + wa
+	cl := ex*ex + ey*ey - wc + wa
+	d := dx*ey - dy*ex
+
+	x := a.X + (ey*bl-dy*cl)*0.5/d
+	y := a.Y + (dx*cl-ex*bl)*0.5/d
+
+	return Point{x, y}
+}
+
 func polygonArea(points []Point) float64 {
 	var result float64
 	for i, p := range points {
@@ -94,3 +112,60 @@ func polygonPerimeter(points []Point) float64 {
 	}
 	return result
 }
+
+func nextHalfedge(e int) int {
+	if e%3 == 2 {
+		return e - 2
+	}
+	return e + 1
+}
+
+func prevHalfedge(e int) int {
+	if e%3 == 0 {
+		return e + 2
+	}
+	return e - 1
+}
+
+// computeHalfedges pairs up the opposite halfedges of the provided triangles,
+// leaving -1 for halfedges that have no twin
+func computeHalfedges(triangles []int) []int {
+	halfedges := make([]int, len(triangles))
+	edges := make(map[[2]int]int, len(triangles))
+	for e := range triangles {
+		halfedges[e] = -1
+		a := triangles[e]
+		b := triangles[nextHalfedge(e)]
+		if h, ok := edges[[2]int{b, a}]; ok {
+			halfedges[e] = h
+			halfedges[h] = e
+			delete(edges, [2]int{b, a})
+		} else {
+			edges[[2]int{a, b}] = e
+		}
+	}
+	return halfedges
+}
+
+// polygonContains reports whether p lies inside the polygon, using the
+// even-odd rule
+func polygonContains(polygon []Point, p Point) bool {
+	inside := false
+	for i, a := range polygon {
+		b := polygon[(i+1)%len(polygon)]
+		if (a.Y > p.Y) != (b.Y > p.Y) && p.X < a.X+(p.Y-a.Y)*(b.X-a.X)/(b.Y-a.Y) {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// reversed reverses the polygon in place and returns it. The triangles wind
+// clockwise, so outlines traced along them are reversed to run
+// counter-clockwise.
+func reversed(polygon []Point) []Point {
+	for i, j := 0, len(polygon)-1; i < j; i, j = i+1, j-1 {
+		polygon[i], polygon[j] = polygon[j], polygon[i]
+	}
+	return polygon
+}
@@ -0,0 +1,47 @@
+package delaunay
+
+import "math"
+
+// Vector is a point or direction in three-dimensional space.
+type Vector struct {
+	X, Y, Z float64
+}
+
+func (a Vector) add(b Vector) Vector {
+	return Vector{a.X + b.X, a.Y + b.Y, a.Z + b.Z}
+}
+
+func (a Vector) sub(b Vector) Vector {
+	return Vector{a.X - b.X, a.Y - b.Y, a.Z - b.Z}
+}
+
+func (a Vector) mulScalar(s float64) Vector {
+	return Vector{a.X * s, a.Y * s, a.Z * s}
+}
+
+func (a Vector) dot(b Vector) float64 {
+	return a.X*b.X + a.Y*b.Y + a.Z*b.Z
+}
+
+func (a Vector) cross(b Vector) Vector {
+	x := a.Y*b.Z - a.Z*b.Y
+	y := a.Z*b.X - a.X*b.Z
+	z := a.X*b.Y - a.Y*b.X
+	return Vector{x, y, z}
+}
+
+func (a Vector) length() float64 {
+	return math.Sqrt(a.X*a.X + a.Y*a.Y + a.Z*a.Z)
+}
+
+func (a Vector) squaredDistance(b Vector) float64 {
+	dx := a.X - b.X
+	dy := a.Y - b.Y
+	dz := a.Z - b.Z
+	return dx*dx + dy*dy + dz*dz
+}
+
+func (a Vector) normalize() Vector {
+	d := a.length()
+	return Vector{a.X / d, a.Y / d, a.Z / d}
+}
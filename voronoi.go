@@ -0,0 +1,15 @@
+package delaunay
+
+// VoronoiCells returns the Voronoi diagram of the triangulated points as one
+// counter-clockwise polygon per point, clipped to the rectangle [min, max].
+// Points that are not part of the triangulation (duplicates), or whose cell
+// lies entirely outside of the rectangle, have nil cells.
+func (t *Triangulation) VoronoiCells(min, max Point) [][]Point {
+	points := t.Points
+	ts := t.Triangles
+	centers := make([]Point, len(ts)/3)
+	for i := range centers {
+		centers[i] = circumcenter(points[ts[i*3]], points[ts[i*3+1]], points[ts[i*3+2]])
+	}
+	return cells(t, centers, min, max)
+}
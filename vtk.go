@@ -0,0 +1,191 @@
+package delaunay
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// VTKField is a named field with one scalar or vector per point or per
+// triangle, attached to the output of WriteVTK and WriteVTU.
+type VTKField struct {
+	Name string
+
+	// Components is 1 for scalars, or 2 or 3 for vectors. Two-dimensional
+	// vectors are written with a zero z component.
+	Components int
+
+	// Values holds Components values per point or triangle.
+	Values []float64
+}
+
+func checkVTKFields(fields []VTKField, n int, kind string) error {
+	for _, f := range fields {
+		if f.Name == "" || strings.ContainsAny(f.Name, " \t\r\n") {
+			return fmt.Errorf("invalid VTK field name: %q", f.Name)
+		}
+		if f.Components < 1 || f.Components > 3 {
+			return fmt.Errorf("VTK field %q has %d components", f.Name, f.Components)
+		}
+		if len(f.Values) != n*f.Components {
+			return fmt.Errorf("VTK field %q has %d values for %d %s", f.Name, len(f.Values), n, kind)
+		}
+	}
+	return nil
+}
+
+// writeVTKValues writes the values of a field, padding vectors to three
+// components, with one scalar or vector per line
+func writeVTKValues(w *bufio.Writer, f VTKField) {
+	for i := 0; i < len(f.Values); i += f.Components {
+		for j := 0; j < 3; j++ {
+			if j > 0 {
+				w.WriteByte(' ')
+			}
+			if j < f.Components {
+				w.WriteString(formatFloat(f.Values[i+j]))
+			} else {
+				w.WriteByte('0')
+			}
+			if f.Components == 1 {
+				break
+			}
+		}
+		w.WriteByte('\n')
+	}
+}
+
+// WriteVTK writes the triangulation as an ASCII legacy VTK unstructured grid
+// with the provided point and triangle fields, in order. Points are placed
+// at z = 0 and triangles are written counter-clockwise, so that their
+// normals point up. Field names must not contain whitespace.
+func (t *Triangulation) WriteVTK(w io.Writer, pointFields, cellFields []VTKField) error {
+	ts := t.Triangles
+	n := len(t.Points)
+	m := len(ts) / 3
+	if err := checkVTKFields(pointFields, n, "points"); err != nil {
+		return err
+	}
+	if err := checkVTKFields(cellFields, m, "triangles"); err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "# vtk DataFile Version 3.0")
+	fmt.Fprintln(bw, "delaunay triangulation")
+	fmt.Fprintln(bw, "ASCII")
+	fmt.Fprintln(bw, "DATASET UNSTRUCTURED_GRID")
+	fmt.Fprintf(bw, "POINTS %d double\n", n)
+	for _, p := range t.Points {
+		fmt.Fprintf(bw, "%s %s 0\n", formatFloat(p.X), formatFloat(p.Y))
+	}
+	fmt.Fprintf(bw, "CELLS %d %d\n", m, 4*m)
+	for i := 0; i < m; i++ {
+		c := t.corners(i)
+		fmt.Fprintf(bw, "3 %d %d %d\n", c[0], c[1], c[2])
+	}
+	fmt.Fprintf(bw, "CELL_TYPES %d\n", m)
+	for i := 0; i < m; i++ {
+		fmt.Fprintln(bw, "5")
+	}
+
+	sections := []struct {
+		header string
+		count  int
+		fields []VTKField
+	}{
+		{"POINT_DATA", n, pointFields},
+		{"CELL_DATA", m, cellFields},
+	}
+	for _, s := range sections {
+		if len(s.fields) == 0 {
+			continue
+		}
+		fmt.Fprintf(bw, "%s %d\n", s.header, s.count)
+		for _, f := range s.fields {
+			if f.Components == 1 {
+				fmt.Fprintf(bw, "SCALARS %s double 1\n", f.Name)
+				fmt.Fprintln(bw, "LOOKUP_TABLE default")
+			} else {
+				fmt.Fprintf(bw, "VECTORS %s double\n", f.Name)
+			}
+			writeVTKValues(bw, f)
+		}
+	}
+	return bw.Flush()
+}
+
+// WriteVTU writes the triangulation as an ASCII XML VTK unstructured grid
+// (.vtu) with the provided point and triangle fields, like WriteVTK.
+func (t *Triangulation) WriteVTU(w io.Writer, pointFields, cellFields []VTKField) error {
+	ts := t.Triangles
+	n := len(t.Points)
+	m := len(ts) / 3
+	if err := checkVTKFields(pointFields, n, "points"); err != nil {
+		return err
+	}
+	if err := checkVTKFields(cellFields, m, "triangles"); err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	dataArray := func(f VTKField) {
+		components := 3
+		if f.Components == 1 {
+			components = 1
+		}
+		fmt.Fprintf(bw, "<DataArray type=\"Float64\" Name=\"%s\" NumberOfComponents=\"%d\" format=\"ascii\">\n",
+			escapeAttr(f.Name), components)
+		writeVTKValues(bw, f)
+		fmt.Fprintln(bw, "</DataArray>")
+	}
+
+	fmt.Fprintln(bw, `<?xml version="1.0"?>`)
+	fmt.Fprintln(bw, `<VTKFile type="UnstructuredGrid" version="0.1" byte_order="LittleEndian">`)
+	fmt.Fprintln(bw, `<UnstructuredGrid>`)
+	fmt.Fprintf(bw, "<Piece NumberOfPoints=\"%d\" NumberOfCells=\"%d\">\n", n, m)
+
+	fmt.Fprintln(bw, `<PointData>`)
+	for _, f := range pointFields {
+		dataArray(f)
+	}
+	fmt.Fprintln(bw, `</PointData>`)
+	fmt.Fprintln(bw, `<CellData>`)
+	for _, f := range cellFields {
+		dataArray(f)
+	}
+	fmt.Fprintln(bw, `</CellData>`)
+
+	fmt.Fprintln(bw, `<Points>`)
+	fmt.Fprintln(bw, `<DataArray type="Float64" NumberOfComponents="3" format="ascii">`)
+	for _, p := range t.Points {
+		fmt.Fprintf(bw, "%s %s 0\n", formatFloat(p.X), formatFloat(p.Y))
+	}
+	fmt.Fprintln(bw, `</DataArray>`)
+	fmt.Fprintln(bw, `</Points>`)
+
+	fmt.Fprintln(bw, `<Cells>`)
+	fmt.Fprintln(bw, `<DataArray type="Int64" Name="connectivity" format="ascii">`)
+	for i := 0; i < m; i++ {
+		c := t.corners(i)
+		fmt.Fprintf(bw, "%d %d %d\n", c[0], c[1], c[2])
+	}
+	fmt.Fprintln(bw, `</DataArray>`)
+	fmt.Fprintln(bw, `<DataArray type="Int64" Name="offsets" format="ascii">`)
+	for i := 1; i <= m; i++ {
+		fmt.Fprintln(bw, 3*i)
+	}
+	fmt.Fprintln(bw, `</DataArray>`)
+	fmt.Fprintln(bw, `<DataArray type="UInt8" Name="types" format="ascii">`)
+	for i := 0; i < m; i++ {
+		fmt.Fprintln(bw, 5)
+	}
+	fmt.Fprintln(bw, `</DataArray>`)
+	fmt.Fprintln(bw, `</Cells>`)
+
+	fmt.Fprintln(bw, `</Piece>`)
+	fmt.Fprintln(bw, `</UnstructuredGrid>`)
+	fmt.Fprintln(bw, `</VTKFile>`)
+	return bw.Flush()
+}
@@ -0,0 +1,36 @@
+package delaunay
+
+import "fmt"
+
+// TriangulateWeighted returns the regular (weighted Delaunay) triangulation of
+// the provided points, using the power distance |p-q|^2 - w in place of the
+// squared Euclidean distance. With all weights equal it is the Delaunay
+// triangulation. Points whose power cell is empty are redundant and do not
+// appear in the triangulation.
+func TriangulateWeighted(points []Point, weights []float64) (*Triangulation, error) {
+	if len(weights) != len(points) {
+		return nil, fmt.Errorf("expected %d weights, got %d", len(points), len(weights))
+	}
+	t := newRegularTriangulator(points, weights)
+	err := t.triangulate()
+	triangles, halfedges := t.finite()
+	return &Triangulation{points, t.convexHull(), triangles, halfedges}, err
+}
+
+// PowerDiagram returns the power diagram (weighted Voronoi diagram) of the
+// provided points as one counter-clockwise polygon per point, clipped to the
+// rectangle [min, max]. Redundant points have nil cells, as do points whose
+// cell lies entirely outside of the rectangle.
+func PowerDiagram(points []Point, weights []float64, min, max Point) ([][]Point, error) {
+	t, err := TriangulateWeighted(points, weights)
+	if err != nil {
+		return nil, err
+	}
+	ts := t.Triangles
+	centers := make([]Point, len(ts)/3)
+	for i := range centers {
+		a, b, c := ts[i*3], ts[i*3+1], ts[i*3+2]
+		centers[i] = powerCenter(points[a], points[b], points[c], weights[a], weights[b], weights[c])
+	}
+	return cells(t, centers, min, max), nil
+}
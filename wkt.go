@@ -0,0 +1,481 @@
+package delaunay
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// OGC geometry type codes used in WKB
+const (
+	wkbPoint        = 1
+	wkbPolygon      = 3
+	wkbMultiPoint   = 4
+	wkbMultiPolygon = 6
+	wkbTIN          = 16
+	wkbTriangle     = 17
+)
+
+// ParseWKT returns the points of a POINT, MULTIPOINT or POLYGON in well-known
+// text, for use as input to Triangulate. The vertices of all of a polygon's
+// rings are returned, without repeating the first point of each ring. Z and
+// M coordinates are ignored, and a PostGIS "SRID=...;" prefix is skipped.
+func ParseWKT(s string) ([]Point, error) {
+	if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(s)), "SRID=") {
+		if i := strings.IndexByte(s, ';'); i >= 0 {
+			s = s[i+1:]
+		}
+	}
+	p := &wktParser{s: s}
+	kind := strings.ToUpper(p.word())
+	if dims := strings.ToUpper(p.peekWord()); dims == "Z" || dims == "M" || dims == "ZM" {
+		p.word()
+	}
+	if strings.ToUpper(p.peekWord()) == "EMPTY" {
+		p.word()
+		if kind != "POINT" && kind != "MULTIPOINT" && kind != "POLYGON" {
+			return nil, fmt.Errorf("unsupported WKT type: %q", kind)
+		}
+		return nil, p.end()
+	}
+
+	var points []Point
+	var err error
+	switch kind {
+	case "POINT":
+		if err = p.expect('('); err != nil {
+			return nil, err
+		}
+		points, err = p.coords(points, 1)
+		if err == nil {
+			err = p.expect(')')
+		}
+	case "MULTIPOINT":
+		err = p.list(func() error {
+			if p.peek() == '(' {
+				p.pos++
+				points, err = p.coords(points, 1)
+				if err != nil {
+					return err
+				}
+				return p.expect(')')
+			}
+			points, err = p.coords(points, 1)
+			return err
+		})
+	case "POLYGON":
+		err = p.list(func() error {
+			if err := p.expect('('); err != nil {
+				return err
+			}
+			n := len(points)
+			points, err = p.coords(points, -1)
+			if err != nil {
+				return err
+			}
+			if len(points)-n > 1 && points[n] == points[len(points)-1] {
+				points = points[:len(points)-1]
+			}
+			return p.expect(')')
+		})
+	default:
+		return nil, fmt.Errorf("unsupported WKT type: %q", kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return points, p.end()
+}
+
+type wktParser struct {
+	s   string
+	pos int
+}
+
+func (p *wktParser) skipSpace() {
+	for p.pos < len(p.s) && strings.IndexByte(" \t\r\n", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *wktParser) peek() byte {
+	p.skipSpace()
+	if p.pos < len(p.s) {
+		return p.s[p.pos]
+	}
+	return 0
+}
+
+// word returns the next run of characters up to a delimiter
+func (p *wktParser) word() string {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.s) && strings.IndexByte(" \t\r\n(),", p.s[p.pos]) < 0 {
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
+
+func (p *wktParser) peekWord() string {
+	pos := p.pos
+	word := p.word()
+	p.pos = pos
+	return word
+}
+
+func (p *wktParser) expect(c byte) error {
+	if p.peek() != c {
+		return fmt.Errorf("expected %q at offset %d of WKT", c, p.pos)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *wktParser) end() error {
+	if p.peek() != 0 {
+		return fmt.Errorf("unexpected %q at offset %d of WKT", p.s[p.pos], p.pos)
+	}
+	return nil
+}
+
+// list parses a parenthesized, comma separated list of items
+func (p *wktParser) list(item func() error) error {
+	if err := p.expect('('); err != nil {
+		return err
+	}
+	for {
+		if err := item(); err != nil {
+			return err
+		}
+		if p.peek() != ',' {
+			return p.expect(')')
+		}
+		p.pos++
+	}
+}
+
+// coords parses up to max (or any number if max < 0) comma separated
+// coordinates, keeping their first two ordinates
+func (p *wktParser) coords(points []Point, max int) ([]Point, error) {
+	for n := 0; ; n++ {
+		var c []float64
+		for p.peek() != ',' && p.peek() != ')' && p.peek() != 0 {
+			word := p.word()
+			x, err := strconv.ParseFloat(word, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid WKT number: %q", word)
+			}
+			c = append(c, x)
+		}
+		if len(c) < 2 {
+			return nil, fmt.Errorf("invalid WKT coordinate at offset %d", p.pos)
+		}
+		points = append(points, Point{c[0], c[1]})
+		if n+1 == max || p.peek() != ',' {
+			return points, nil
+		}
+		p.pos++
+	}
+}
+
+func formatWKTFloat(x float64) string {
+	return strconv.FormatFloat(x, 'f', -1, 64)
+}
+
+// wktRing formats a polygon as a closed WKT ring
+func wktRing(b *strings.Builder, polygon []Point) {
+	b.WriteByte('(')
+	for i := 0; i <= len(polygon); i++ {
+		p := polygon[i%len(polygon)]
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString(formatWKTFloat(p.X))
+		b.WriteByte(' ')
+		b.WriteString(formatWKTFloat(p.Y))
+	}
+	b.WriteByte(')')
+}
+
+// polygons returns the triangles as counter-clockwise polygons
+func (t *Triangulation) polygons() [][]Point {
+	points := t.Points
+	result := make([][]Point, len(t.Triangles)/3)
+	for i := range result {
+		c := t.corners(i)
+		result[i] = []Point{points[c[0]], points[c[1]], points[c[2]]}
+	}
+	return result
+}
+
+func (t *Triangulation) polygonsWKT(kind string) string {
+	polygons := t.polygons()
+	if len(polygons) == 0 {
+		return kind + " EMPTY"
+	}
+	var b strings.Builder
+	b.WriteString(kind)
+	b.WriteString(" (")
+	for i, polygon := range polygons {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteByte('(')
+		wktRing(&b, polygon)
+		b.WriteByte(')')
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+// WKT returns the triangles as a TIN in well-known text. Like all of the WKT
+// and WKB output, the rings are closed and counter-clockwise.
+func (t *Triangulation) WKT() string {
+	return t.polygonsWKT("TIN")
+}
+
+// MultiPolygonWKT returns the triangles as a MULTIPOLYGON in well-known text.
+func (t *Triangulation) MultiPolygonWKT() string {
+	return t.polygonsWKT("MULTIPOLYGON")
+}
+
+// HullWKT returns the convex hull as a POLYGON in well-known text.
+func (t *Triangulation) HullWKT() string {
+	if len(t.ConvexHull) == 0 {
+		return "POLYGON EMPTY"
+	}
+	var b strings.Builder
+	b.WriteString("POLYGON (")
+	wktRing(&b, t.ConvexHull)
+	b.WriteByte(')')
+	return b.String()
+}
+
+// ParseWKB returns the points of a POINT, MULTIPOINT or POLYGON in
+// well-known binary in either byte order, like ParseWKT. Both the ISO and the
+// PostGIS (EWKB) encodings of Z and M coordinates and SRIDs are supported.
+func ParseWKB(data []byte) ([]Point, error) {
+	r := &wkbReader{data: data}
+	points, err := r.geometry(nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	if r.pos != len(data) {
+		return nil, fmt.Errorf("unexpected data after WKB geometry")
+	}
+	return points, nil
+}
+
+type wkbReader struct {
+	data  []byte
+	pos   int
+	order binary.ByteOrder
+}
+
+func (r *wkbReader) uint32() (uint32, error) {
+	if r.pos+4 > len(r.data) {
+		return 0, fmt.Errorf("unexpected end of WKB")
+	}
+	x := r.order.Uint32(r.data[r.pos:])
+	r.pos += 4
+	return x, nil
+}
+
+func (r *wkbReader) float64() (float64, error) {
+	if r.pos+8 > len(r.data) {
+		return 0, fmt.Errorf("unexpected end of WKB")
+	}
+	x := math.Float64frombits(r.order.Uint64(r.data[r.pos:]))
+	r.pos += 8
+	return x, nil
+}
+
+// coord reads a coordinate of the given dimension, keeping x and y
+func (r *wkbReader) coord(points []Point, dims int) ([]Point, error) {
+	var c [4]float64
+	for i := 0; i < dims; i++ {
+		x, err := r.float64()
+		if err != nil {
+			return nil, err
+		}
+		c[i] = x
+	}
+	return append(points, Point{c[0], c[1]}), nil
+}
+
+// geometry reads a geometry; nested is the expected type of a geometry
+// inside of a multi geometry, or 0 at the top level
+func (r *wkbReader) geometry(points []Point, nested uint32) ([]Point, error) {
+	if r.pos >= len(r.data) {
+		return nil, fmt.Errorf("unexpected end of WKB")
+	}
+	switch r.data[r.pos] {
+	case 0:
+		r.order = binary.BigEndian
+	case 1:
+		r.order = binary.LittleEndian
+	default:
+		return nil, fmt.Errorf("invalid WKB byte order: %d", r.data[r.pos])
+	}
+	r.pos++
+	kind, err := r.uint32()
+	if err != nil {
+		return nil, err
+	}
+
+	// PostGIS flags and ISO dimension offsets
+	dims := 2
+	if kind&0x80000000 != 0 {
+		dims++
+	}
+	if kind&0x40000000 != 0 {
+		dims++
+	}
+	if kind&0x20000000 != 0 {
+		if _, err := r.uint32(); err != nil {
+			return nil, err
+		}
+	}
+	kind &= 0x0fffffff
+	switch kind / 1000 {
+	case 1, 2:
+		dims++
+	case 3:
+		dims += 2
+	}
+	kind %= 1000
+	if nested != 0 && kind != nested {
+		return nil, fmt.Errorf("unexpected WKB type %d inside of a multi geometry", kind)
+	}
+
+	switch kind {
+	case wkbPoint:
+		n := len(points)
+		points, err = r.coord(points, dims)
+		if err != nil {
+			return nil, err
+		}
+		if math.IsNaN(points[n].X) && math.IsNaN(points[n].Y) {
+			// empty point
+			points = points[:n]
+		}
+		return points, nil
+	case wkbMultiPoint:
+		if nested != 0 {
+			break
+		}
+		n, err := r.uint32()
+		if err != nil {
+			return nil, err
+		}
+		for i := uint32(0); i < n; i++ {
+			if points, err = r.geometry(points, wkbPoint); err != nil {
+				return nil, err
+			}
+		}
+		return points, nil
+	case wkbPolygon:
+		rings, err := r.uint32()
+		if err != nil {
+			return nil, err
+		}
+		for i := uint32(0); i < rings; i++ {
+			n, err := r.uint32()
+			if err != nil {
+				return nil, err
+			}
+			start := len(points)
+			for j := uint32(0); j < n; j++ {
+				if points, err = r.coord(points, dims); err != nil {
+					return nil, err
+				}
+			}
+			if len(points)-start > 1 && points[start] == points[len(points)-1] {
+				points = points[:len(points)-1]
+			}
+		}
+		return points, nil
+	}
+	return nil, fmt.Errorf("unsupported WKB type: %d", kind)
+}
+
+// wkbWriter writes WKB in a single byte order
+type wkbWriter struct {
+	bytes.Buffer
+	order binary.ByteOrder
+}
+
+func newWKBWriter(order binary.ByteOrder) *wkbWriter {
+	if order == nil {
+		order = binary.LittleEndian
+	}
+	return &wkbWriter{order: order}
+}
+
+func (w *wkbWriter) header(kind uint32) {
+	if w.order == binary.BigEndian {
+		w.WriteByte(0)
+	} else {
+		w.WriteByte(1)
+	}
+	w.uint32(kind)
+}
+
+func (w *wkbWriter) uint32(x uint32) {
+	var buf [4]byte
+	w.order.PutUint32(buf[:], x)
+	w.Write(buf[:])
+}
+
+func (w *wkbWriter) polygon(kind uint32, polygon []Point) {
+	w.header(kind)
+	w.uint32(1)
+	w.uint32(uint32(len(polygon) + 1))
+	var buf [8]byte
+	for i := 0; i <= len(polygon); i++ {
+		p := polygon[i%len(polygon)]
+		w.order.PutUint64(buf[:], math.Float64bits(p.X))
+		w.Write(buf[:])
+		w.order.PutUint64(buf[:], math.Float64bits(p.Y))
+		w.Write(buf[:])
+	}
+}
+
+func (t *Triangulation) polygonsWKB(order binary.ByteOrder, kind, element uint32) []byte {
+	w := newWKBWriter(order)
+	polygons := t.polygons()
+	w.header(kind)
+	w.uint32(uint32(len(polygons)))
+	for _, polygon := range polygons {
+		w.polygon(element, polygon)
+	}
+	return w.Bytes()
+}
+
+// WKB returns the triangles as a TIN of TRIANGLEs in well-known binary, in
+// the provided byte order (binary.LittleEndian or binary.BigEndian).
+func (t *Triangulation) WKB(order binary.ByteOrder) []byte {
+	return t.polygonsWKB(order, wkbTIN, wkbTriangle)
+}
+
+// MultiPolygonWKB returns the triangles as a MULTIPOLYGON in well-known
+// binary, in the provided byte order.
+func (t *Triangulation) MultiPolygonWKB(order binary.ByteOrder) []byte {
+	return t.polygonsWKB(order, wkbMultiPolygon, wkbPolygon)
+}
+
+// HullWKB returns the convex hull as a POLYGON in well-known binary, in the
+// provided byte order.
+func (t *Triangulation) HullWKB(order binary.ByteOrder) []byte {
+	w := newWKBWriter(order)
+	if len(t.ConvexHull) == 0 {
+		w.header(wkbPolygon)
+		w.uint32(0)
+	} else {
+		w.polygon(wkbPolygon, t.ConvexHull)
+	}
+	return w.Bytes()
+}